@@ -2,11 +2,15 @@ package csd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -19,7 +23,103 @@ type ApiClient struct {
 	AccessKeyId     string
 	SecretAccessKey string
 	SessionToken    string
+	Region          string
+	Route53Endpoint string
 	UserAgent       string
+
+	limiter *requestLimiter
+}
+
+// Summaries of the diagnostics for objects missing in the API, see isNotFound
+const (
+	summaryZoneDelegationNotFound = "Couldn't find zone delegation with given name"
+	summaryRecordNotFound         = "Couldn't find record with given name"
+)
+
+// isNotFound reports whether diagnostics only tell that the requested object doesn't exist
+func isNotFound(diags diag.Diagnostics) bool {
+	if len(diags) == 0 {
+		return false
+	}
+	for _, d := range diags {
+		if d.Summary != summaryZoneDelegationNotFound && d.Summary != summaryRecordNotFound {
+			return false
+		}
+	}
+	return true
+}
+
+// attemptTimeout Limits a single attempt of a request, so a hung connection leaves time for retries
+const attemptTimeout = 30 * time.Second
+
+// send signs and executes a request against the API. Failed attempts are retried until the deadline of ctx,
+// which resources derive from their timeouts, as far as isRetryable allows it.
+// Every attempt waits for the request limits of the provider, the response body holds its slot until it's closed.
+func (c *ApiClient) send(ctx context.Context, method string, path string, payload []byte) (*http.Response, error) {
+	client := &http.Client{Timeout: attemptTimeout}
+	backoff := time.Second
+
+	for {
+		// Waiting comes first, so the signature is fresh when the request is sent
+		release, err := c.limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", HostURL, path), bytes.NewReader(payload))
+		if err != nil {
+			release()
+			return nil, err
+		}
+		authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
+		request.Header.Add("X-Amz-Security-Token", c.SessionToken)
+		request.Header.Add("X-Amz-Date", authorizationHeaders.date)
+		request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
+		request.Header.Add("content-type", "application/json")
+		request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
+		request.Header.Set("User-Agent", c.UserAgent)
+
+		response, err := client.Do(request)
+		if err != nil {
+			release()
+		} else {
+			response.Body = limitedBody{ReadCloser: response.Body, release: release}
+		}
+		// Report the last attempt if it can't be retried or there is no deadline or no time left for another one
+		if !isRetryable(method, response, err) {
+			return response, err
+		}
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < backoff {
+			return response, err
+		}
+		if err == nil {
+			response.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, 10*time.Second)
+	}
+}
+
+// isRetryable reports whether a failed attempt is worth another one. A POST may have been committed even if
+// the response got lost, so it is only repeated if the API surely didn't process it: on throttling or if the
+// connection couldn't be established at all.
+func isRetryable(method string, response *http.Response, err error) bool {
+	idempotent := method != http.MethodPost
+	if err != nil {
+		var opErr *net.OpError
+		return idempotent || (errors.As(err, &opErr) && opErr.Op == "dial")
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
 }
 
 // Zone Delegation
@@ -27,9 +127,19 @@ type ApiClient struct {
 type ZoneDelegation struct {
 	Name        string   `json:"name"`
 	NameServers []string `json:"name_servers"`
+	// DSRecords Are left unchanged by the API if nil, an empty slice removes them
+	DSRecords []DSRecord `json:"ds_records,omitzero"`
+}
+
+// DSRecord Links the DNSSEC chain of trust from the parent zone to the delegated zone
+type DSRecord struct {
+	KeyTag     int    `json:"key_tag"`
+	Algorithm  int    `json:"algorithm"`
+	DigestType int    `json:"digest_type"`
+	Digest     string `json:"digest"`
 }
 
-func (c *ApiClient) createZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDelegation, diag.Diagnostics) {
+func (c *ApiClient) createZoneDelegation(ctx context.Context, zoneDelegation ZoneDelegation) (ZoneDelegation, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	buffer := new(bytes.Buffer)
@@ -37,20 +147,7 @@ func (c *ApiClient) createZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDel
 		return zoneDelegation, diag.FromErr(err)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v2/zone_delegations", HostURL), buffer)
-	if err != nil {
-		return zoneDelegation, diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodPost, "/v2/zone_delegations", buffer.Bytes())
 	if err != nil {
 		return zoneDelegation, diag.FromErr(err)
 	}
@@ -93,24 +190,11 @@ func (c *ApiClient) createZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDel
 	return zoneDelegation, diags
 }
 
-func (c *ApiClient) getZoneDelegation(name string) (ZoneDelegation, diag.Diagnostics) {
+func (c *ApiClient) getZoneDelegation(ctx context.Context, name string) (ZoneDelegation, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	var zoneDelegation ZoneDelegation
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/zone_delegations/%s", HostURL, name), strings.NewReader(""))
-	if err != nil {
-		return zoneDelegation, diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodGet, fmt.Sprintf("/v2/zone_delegations/%s", name), nil)
 	if err != nil {
 		return zoneDelegation, diag.FromErr(err)
 	}
@@ -134,7 +218,7 @@ func (c *ApiClient) getZoneDelegation(name string) (ZoneDelegation, diag.Diagnos
 		}
 		return zoneDelegation, append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Couldn't find zone delegation with given name",
+			Summary:  summaryZoneDelegationNotFound,
 			Detail:   responseBody["message"],
 		})
 	} else if response.StatusCode != 200 {
@@ -153,24 +237,11 @@ func (c *ApiClient) getZoneDelegation(name string) (ZoneDelegation, diag.Diagnos
 	return zoneDelegation, diags
 }
 
-func (c *ApiClient) getZoneDelegations() ([]ZoneDelegation, diag.Diagnostics) {
+func (c *ApiClient) getZoneDelegations(ctx context.Context) ([]ZoneDelegation, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	var zoneDelegations []ZoneDelegation
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/zone_delegations", HostURL), strings.NewReader(""))
-	if err != nil {
-		return zoneDelegations, diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodGet, "/v2/zone_delegations", nil)
 	if err != nil {
 		return zoneDelegations, diag.FromErr(err)
 	}
@@ -203,7 +274,7 @@ func (c *ApiClient) getZoneDelegations() ([]ZoneDelegation, diag.Diagnostics) {
 	return zoneDelegations, diags
 }
 
-func (c *ApiClient) updateZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDelegation, diag.Diagnostics) {
+func (c *ApiClient) updateZoneDelegation(ctx context.Context, zoneDelegation ZoneDelegation) (ZoneDelegation, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	buffer := new(bytes.Buffer)
@@ -211,20 +282,7 @@ func (c *ApiClient) updateZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDel
 		return zoneDelegation, diag.FromErr(err)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v2/zone_delegations/%s", HostURL, zoneDelegation.Name), buffer)
-	if err != nil {
-		return zoneDelegation, diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodPut, fmt.Sprintf("/v2/zone_delegations/%s", zoneDelegation.Name), buffer.Bytes())
 	if err != nil {
 		return zoneDelegation, diag.FromErr(err)
 	}
@@ -248,7 +306,7 @@ func (c *ApiClient) updateZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDel
 		}
 		return zoneDelegation, append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Couldn't find zone delegation with given name",
+			Summary:  summaryZoneDelegationNotFound,
 			Detail:   responseBody["message"],
 		})
 	} else if response.StatusCode != 200 {
@@ -267,23 +325,10 @@ func (c *ApiClient) updateZoneDelegation(zoneDelegation ZoneDelegation) (ZoneDel
 	return zoneDelegation, diags
 }
 
-func (c *ApiClient) deleteZoneDelegation(name string) diag.Diagnostics {
+func (c *ApiClient) deleteZoneDelegation(ctx context.Context, name string) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v2/zone_delegations/%s", HostURL, name), strings.NewReader(""))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodDelete, fmt.Sprintf("/v2/zone_delegations/%s", name), nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -307,7 +352,7 @@ func (c *ApiClient) deleteZoneDelegation(name string) diag.Diagnostics {
 		}
 		return append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Couldn't find zone delegation with given name",
+			Summary:  summaryZoneDelegationNotFound,
 			Detail:   responseBody["message"],
 		})
 	} else if response.StatusCode != 204 {
@@ -332,7 +377,7 @@ type Record struct {
 	RRType string `json:"rrtype"`
 }
 
-func (c *ApiClient) createRecord(record Record) (Record, diag.Diagnostics) {
+func (c *ApiClient) createRecord(ctx context.Context, record Record) (Record, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	buffer := new(bytes.Buffer)
@@ -340,20 +385,7 @@ func (c *ApiClient) createRecord(record Record) (Record, diag.Diagnostics) {
 		return record, diag.FromErr(err)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v2/records", HostURL), buffer)
-	if err != nil {
-		return record, diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodPost, "/v2/records", buffer.Bytes())
 	if err != nil {
 		return record, diag.FromErr(err)
 	}
@@ -396,24 +428,11 @@ func (c *ApiClient) createRecord(record Record) (Record, diag.Diagnostics) {
 	return record, diags
 }
 
-func (c *ApiClient) getRecord(name string) (Record, diag.Diagnostics) {
+func (c *ApiClient) getRecord(ctx context.Context, name string) (Record, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	var record Record
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/records/%s", HostURL, name), strings.NewReader(""))
-	if err != nil {
-		return record, diag.FromErr(err)
-	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
-
-	response, err := client.Do(request)
+	response, err := c.send(ctx, http.MethodGet, fmt.Sprintf("/v2/records/%s", name), nil)
 	if err != nil {
 		return record, diag.FromErr(err)
 	}
@@ -437,7 +456,7 @@ func (c *ApiClient) getRecord(name string) (Record, diag.Diagnostics) {
 		}
 		return record, append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Couldn't find record with given name",
+			Summary:  summaryRecordNotFound,
 			Detail:   responseBody["message"],
 		})
 	} else if response.StatusCode != 200 {
@@ -456,24 +475,41 @@ func (c *ApiClient) getRecord(name string) (Record, diag.Diagnostics) {
 	return record, diags
 }
 
-func (c *ApiClient) getRecords() ([]Record, diag.Diagnostics) {
-	var diags diag.Diagnostics
-	var record []Record
+// RecordFilter Narrows down the records returned by the API, empty fields match everything
+type RecordFilter struct {
+	RRType     string
+	NameSuffix string
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/records", HostURL), strings.NewReader(""))
-	if err != nil {
-		return record, diag.FromErr(err)
+// query Encodes the filter as query parameters for the API
+func (f RecordFilter) query() string {
+	query := url.Values{}
+	if f.RRType != "" {
+		query.Set("rrtype", f.RRType)
+	}
+	if f.NameSuffix != "" {
+		query.Set("name_suffix", f.NameSuffix)
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// matches Reports whether record passes the filter, for APIs that ignore it
+func (f RecordFilter) matches(record Record) bool {
+	if f.RRType != "" && !strings.EqualFold(record.RRType, f.RRType) {
+		return false
 	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
+	return hasNameSuffix(record.Name, f.NameSuffix)
+}
 
-	response, err := client.Do(request)
+// getRecords Lists the records, the API may ignore the filter so callers have to apply it again
+func (c *ApiClient) getRecords(ctx context.Context, filter RecordFilter) ([]Record, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var record []Record
+
+	response, err := c.send(ctx, http.MethodGet, "/v2/records"+filter.query(), nil)
 	if err != nil {
 		return record, diag.FromErr(err)
 	}
@@ -506,30 +542,62 @@ func (c *ApiClient) getRecords() ([]Record, diag.Diagnostics) {
 	return record, diags
 }
 
-func (c *ApiClient) updateRecord(record Record) (Record, diag.Diagnostics) {
+// findRecord looks up a single record by name and optionally type. Without a type the name has to be
+// unambiguous, e.g. there may not be a TXT and a CNAME record with the same name. With a type the record the API
+// returns for the name is used if it matches, only otherwise the list of records is needed.
+func (c *ApiClient) findRecord(ctx context.Context, name string, rrtype string) (Record, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	buffer := new(bytes.Buffer)
-	if err := json.NewEncoder(buffer).Encode(record); err != nil {
-		return record, diag.FromErr(err)
+	if rrtype != "" {
+		record, err := c.getRecord(ctx, canonicalName(name))
+		if err != nil || strings.EqualFold(record.RRType, rrtype) {
+			return record, err
+		}
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v2/records/%s", HostURL, record.Name), buffer)
+	// Either the name holds a record of another type or all records of the name are needed to rule out ambiguity
+	results, err := c.getRecords(ctx, RecordFilter{RRType: strings.ToUpper(rrtype), NameSuffix: canonicalName(name)})
 	if err != nil {
-		return record, diag.FromErr(err)
+		return Record{}, err
 	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
 
-	response, err := client.Do(request)
+	var matches []Record
+	var types []string
+	for _, result := range results {
+		if canonicalName(result.Name) != canonicalName(name) || (rrtype != "" && !strings.EqualFold(result.RRType, rrtype)) {
+			continue
+		}
+		matches = append(matches, result)
+		types = append(types, result.RRType)
+	}
+
+	if len(matches) == 0 {
+		detail := fmt.Sprintf("There is no record named %s", name)
+		if rrtype != "" {
+			detail = fmt.Sprintf("There is no %s record named %s", strings.ToUpper(rrtype), name)
+		}
+		return Record{}, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summaryRecordNotFound,
+			Detail:   detail,
+		})
+	} else if len(matches) > 1 {
+		return Record{}, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Found several records with given name",
+			Detail:   fmt.Sprintf("There are records of types %s named %s, please set rrtype to pick one", strings.Join(types, ", "), name),
+		})
+	}
+
+	return matches[0], diags
+}
+
+func (c *ApiClient) deleteRecord(ctx context.Context, name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	response, err := c.send(ctx, http.MethodDelete, fmt.Sprintf("/v2/records/%s", name), nil)
 	if err != nil {
-		return record, diag.FromErr(err)
+		return diag.FromErr(err)
 	}
 	defer response.Body.Close()
 
@@ -537,9 +605,9 @@ func (c *ApiClient) updateRecord(record Record) (Record, diag.Diagnostics) {
 		// Create proper error message if AWS credentials are not valid, probably because they expired
 		var responseBody map[string]string
 		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
-			return record, diag.FromErr(err)
+			return diag.FromErr(err)
 		}
-		return record, append(diags, diag.Diagnostic{
+		return append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Couldn't authenticate to API, please check AWS credentials",
 			Detail:   responseBody["message"],
@@ -547,48 +615,116 @@ func (c *ApiClient) updateRecord(record Record) (Record, diag.Diagnostics) {
 	} else if response.StatusCode == 404 {
 		var responseBody map[string]string
 		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
-			return record, diag.FromErr(err)
+			return diag.FromErr(err)
 		}
-		return record, append(diags, diag.Diagnostic{
+		return append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Couldn't find record with given id",
+			Summary:  summaryRecordNotFound,
 			Detail:   responseBody["message"],
 		})
-	} else if response.StatusCode != 200 {
+	} else if response.StatusCode != 204 {
 		// Create error message for any other unexpected errors
 		body, _ := io.ReadAll(response.Body)
-		return record, append(diags, diag.Diagnostic{
+		return append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unexpected error message from API",
 			Detail:   fmt.Sprintf("HTTP %d: %s", response.StatusCode, body),
 		})
 	}
 
-	if err := json.NewDecoder(response.Body).Decode(&record); err != nil {
-		return record, diag.FromErr(err)
-	}
-	return record, diags
+	return diags
+}
+
+// Change Set
+
+// Actions of a RecordChange
+const (
+	ChangeActionCreate = "CREATE"
+	ChangeActionUpdate = "UPDATE"
+	ChangeActionDelete = "DELETE"
+)
+
+// Statuses of a ChangeSet, the API applies all of its changes or none of them
+const (
+	ChangeStatusPending = "PENDING"
+	ChangeStatusApplied = "APPLIED"
+	ChangeStatusFailed  = "FAILED"
+)
+
+// changeSetPollInterval Is the pause between status queries of a pending change set
+const changeSetPollInterval = 2 * time.Second
+
+type RecordChange struct {
+	Action string `json:"action"`
+	Record Record `json:"record"`
 }
 
-func (c *ApiClient) deleteRecord(name string) diag.Diagnostics {
+type ChangeSet struct {
+	Id      string         `json:"id,omitempty"`
+	Status  string         `json:"status,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Changes []RecordChange `json:"changes"`
+}
+
+func (c *ApiClient) createChangeSet(ctx context.Context, changes []RecordChange) (ChangeSet, diag.Diagnostics) {
 	var diags diag.Diagnostics
+	changeSet := ChangeSet{Changes: changes}
+
+	buffer := new(bytes.Buffer)
+	if err := json.NewEncoder(buffer).Encode(changeSet); err != nil {
+		return changeSet, diag.FromErr(err)
+	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v2/records/%s", HostURL, name), strings.NewReader(""))
+	response, err := c.send(ctx, http.MethodPost, "/v2/changes", buffer.Bytes())
 	if err != nil {
-		return diag.FromErr(err)
+		return changeSet, diag.FromErr(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == 403 {
+		// Create proper error message if AWS credentials are not valid, probably because they expired
+		var responseBody map[string]string
+		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
+			return changeSet, diag.FromErr(err)
+		}
+		return changeSet, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Couldn't authenticate to API, please check AWS credentials",
+			Detail:   responseBody["message"],
+		})
+	} else if response.StatusCode == 400 || response.StatusCode == 409 {
+		var responseBody map[string]string
+		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
+			return changeSet, diag.FromErr(err)
+		}
+		return changeSet, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Couldn't submit change set",
+			Detail:   responseBody["message"],
+		})
+	} else if response.StatusCode != 202 {
+		// Create error message for any other unexpected errors
+		body, _ := io.ReadAll(response.Body)
+		return changeSet, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unexpected error message from API",
+			Detail:   fmt.Sprintf("HTTP %d: %s", response.StatusCode, body),
+		})
 	}
-	authorizationHeaders := signRequest(request, c.AccessKeyId, c.SecretAccessKey, c.SessionToken)
-	request.Header.Add("X-Amz-Security-Token", c.SessionToken)
-	request.Header.Add("X-Amz-Date", authorizationHeaders.date)
-	request.Header.Add("Authorization", authorizationHeaders.authorizationHeaders)
-	request.Header.Add("content-type", "application/json")
-	request.Header.Add("x-amz-content-sha256", fmt.Sprintf("%x", authorizationHeaders.payloadHash))
-	request.Header.Set("User-Agent", c.UserAgent)
 
-	response, err := client.Do(request)
+	if err := json.NewDecoder(response.Body).Decode(&changeSet); err != nil {
+		return changeSet, diag.FromErr(err)
+	}
+	return changeSet, diags
+}
+
+func (c *ApiClient) getChangeSet(ctx context.Context, id string) (ChangeSet, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var changeSet ChangeSet
+
+	response, err := c.send(ctx, http.MethodGet, fmt.Sprintf("/v2/changes/%s", id), nil)
 	if err != nil {
-		return diag.FromErr(err)
+		return changeSet, diag.FromErr(err)
 	}
 	defer response.Body.Close()
 
@@ -596,9 +732,9 @@ func (c *ApiClient) deleteRecord(name string) diag.Diagnostics {
 		// Create proper error message if AWS credentials are not valid, probably because they expired
 		var responseBody map[string]string
 		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
-			return diag.FromErr(err)
+			return changeSet, diag.FromErr(err)
 		}
-		return append(diags, diag.Diagnostic{
+		return changeSet, append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Couldn't authenticate to API, please check AWS credentials",
 			Detail:   responseBody["message"],
@@ -606,22 +742,154 @@ func (c *ApiClient) deleteRecord(name string) diag.Diagnostics {
 	} else if response.StatusCode == 404 {
 		var responseBody map[string]string
 		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
-			return diag.FromErr(err)
+			return changeSet, diag.FromErr(err)
 		}
-		return append(diags, diag.Diagnostic{
+		return changeSet, append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  "Couldn't find record with given name",
+			Summary:  "Couldn't find change set with given id",
 			Detail:   responseBody["message"],
 		})
-	} else if response.StatusCode != 204 {
+	} else if response.StatusCode != 200 {
 		// Create error message for any other unexpected errors
 		body, _ := io.ReadAll(response.Body)
-		return append(diags, diag.Diagnostic{
+		return changeSet, append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unexpected error message from API",
 			Detail:   fmt.Sprintf("HTTP %d: %s", response.StatusCode, body),
 		})
 	}
 
+	if err := json.NewDecoder(response.Body).Decode(&changeSet); err != nil {
+		return changeSet, diag.FromErr(err)
+	}
+	return changeSet, diags
+}
+
+// applyChanges Submits the changes as one change set and polls its status until the API applied or rejected
+// all of them, or the deadline of ctx expires
+func (c *ApiClient) applyChanges(ctx context.Context, changes []RecordChange) diag.Diagnostics {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changeSet, diags := c.createChangeSet(ctx, changes)
+	for !diags.HasError() && changeSet.Status == ChangeStatusPending {
+		select {
+		case <-ctx.Done():
+			return append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Change set %s is still pending", changeSet.Id),
+				Detail:   "The changes may still be applied, please check the records before trying again.",
+			})
+		case <-time.After(changeSetPollInterval):
+		}
+		changeSet, diags = c.getChangeSet(ctx, changeSet.Id)
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	if changeSet.Status != ChangeStatusApplied {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Couldn't apply change set %s", changeSet.Id),
+			Detail:   fmt.Sprintf("None of the %d changes were applied: %s", len(changes), changeSet.Message),
+		})
+	}
 	return diags
 }
+
+// Domain
+
+type Domain struct {
+	Name           string   `json:"name"`
+	RRTypes        []string `json:"rrtypes"`
+	MinTTL         int      `json:"min_ttl"`
+	MaxTTL         int      `json:"max_ttl"`
+	MaxLabelLength int      `json:"max_label_length"`
+}
+
+func (c *ApiClient) getDomains(ctx context.Context) ([]Domain, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var domains []Domain
+
+	response, err := c.send(ctx, http.MethodGet, "/v2/domains", nil)
+	if err != nil {
+		return domains, diag.FromErr(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == 403 {
+		// Create proper error message if AWS credentials are not valid, probably because they expired
+		var responseBody map[string]string
+		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
+			return domains, diag.FromErr(err)
+		}
+		return domains, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Couldn't authenticate to API, please check AWS credentials",
+			Detail:   responseBody["message"],
+		})
+	} else if response.StatusCode != 200 {
+		// Create error message for any other unexpected errors
+		body, _ := io.ReadAll(response.Body)
+		return domains, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unexpected error message from API",
+			Detail:   fmt.Sprintf("HTTP %d: %s", response.StatusCode, body),
+		})
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&domains); err != nil {
+		return domains, diag.FromErr(err)
+	}
+	return domains, diags
+}
+
+// Caller Identity
+
+// Owner tells which CSD tenant the calling AWS principal is mapped to
+type Owner struct {
+	Owner string `json:"owner"`
+}
+
+// getOwner returns the tenant the caller maps to, or an empty owner if the API doesn't support it
+func (c *ApiClient) getOwner(ctx context.Context) (Owner, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var owner Owner
+
+	response, err := c.send(ctx, http.MethodGet, "/v2/caller_identity", nil)
+	if err != nil {
+		return owner, diag.FromErr(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == 403 {
+		// Create proper error message if AWS credentials are not valid, probably because they expired
+		var responseBody map[string]string
+		if err = json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
+			return owner, diag.FromErr(err)
+		}
+		return owner, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Couldn't authenticate to API, please check AWS credentials",
+			Detail:   responseBody["message"],
+		})
+	} else if response.StatusCode == 404 {
+		// Older API versions don't know about owners
+		return owner, diags
+	} else if response.StatusCode != 200 {
+		// Create error message for any other unexpected errors
+		body, _ := io.ReadAll(response.Body)
+		return owner, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unexpected error message from API",
+			Detail:   fmt.Sprintf("HTTP %d: %s", response.StatusCode, body),
+		})
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&owner); err != nil {
+		return owner, diag.FromErr(err)
+	}
+	return owner, diags
+}
@@ -0,0 +1,227 @@
+package csd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTestAPI Sends all API and Route53 requests of the test to handler instead of HostURL and AWS
+func newTestAPI(t *testing.T, handler http.Handler) *ApiClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		request.URL.Scheme = "http"
+		request.URL.Host = strings.TrimPrefix(server.URL, "http://")
+		return transport.RoundTrip(request)
+	})
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	return &ApiClient{AccessKeyId: "AKIDEXAMPLE", SecretAccessKey: "secret", Region: "eu-central-1", Route53Endpoint: server.URL}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// recordsAPI Serves records like the API, one name may hold records of several types. Change sets are applied
+// atomically and fail as a whole if a record to delete or update doesn't exist as given.
+type recordsAPI struct {
+	mutex      sync.Mutex
+	records    []Record
+	requests   []string
+	changeSets []ChangeSet
+}
+
+func (a *recordsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v2/records":
+		json.NewEncoder(w).Encode(a.records)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v2/records/"):
+		name := strings.TrimPrefix(r.URL.Path, "/v2/records/")
+		for _, record := range a.records {
+			if record.Name == name {
+				json.NewEncoder(w).Encode(record)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/changes":
+		var changeSet ChangeSet
+		json.NewDecoder(r.Body).Decode(&changeSet)
+		changeSet.Id = fmt.Sprint(len(a.changeSets) + 1)
+		if err := a.apply(changeSet.Changes); err != nil {
+			changeSet.Status, changeSet.Message = ChangeStatusFailed, err.Error()
+		} else {
+			changeSet.Status = ChangeStatusApplied
+		}
+		a.changeSets = append(a.changeSets, changeSet)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(changeSet)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *recordsAPI) apply(changes []RecordChange) error {
+	records := slices.Clone(a.records)
+	for _, change := range changes {
+		i := slices.IndexFunc(records, func(record Record) bool { return newRecordKey(record) == newRecordKey(change.Record) })
+		switch {
+		case change.Action == ChangeActionCreate && i < 0:
+			records = append(records, change.Record)
+		case change.Action == ChangeActionUpdate && i >= 0:
+			records[i] = change.Record
+		case change.Action == ChangeActionDelete && i >= 0 && canonicalRecord(records[i]) == canonicalRecord(change.Record):
+			records = slices.Delete(records, i, i+1)
+		default:
+			return fmt.Errorf("can't %s %s record %s", change.Action, change.Record.RRType, change.Record.Name)
+		}
+	}
+	a.records = records
+	return nil
+}
+
+func TestIsRetryable(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		err        error
+		want       bool
+	}{
+		{name: "GET ok", method: http.MethodGet, statusCode: 200, want: false},
+		{name: "GET not found", method: http.MethodGet, statusCode: 404, want: false},
+		{name: "GET throttled", method: http.MethodGet, statusCode: 429, want: true},
+		{name: "GET bad gateway", method: http.MethodGet, statusCode: 502, want: true},
+		{name: "GET connection reset", method: http.MethodGet, err: readErr, want: true},
+		{name: "PUT server error", method: http.MethodPut, statusCode: 500, want: true},
+		{name: "DELETE unavailable", method: http.MethodDelete, statusCode: 503, want: true},
+		{name: "POST created", method: http.MethodPost, statusCode: 201, want: false},
+		{name: "POST throttled", method: http.MethodPost, statusCode: 429, want: true},
+		{name: "POST bad gateway", method: http.MethodPost, statusCode: 502, want: false},
+		{name: "POST gateway timeout", method: http.MethodPost, statusCode: 504, want: false},
+		{name: "POST connection refused", method: http.MethodPost, err: dialErr, want: true},
+		{name: "POST connection reset", method: http.MethodPost, err: readErr, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response *http.Response
+			if tt.err == nil {
+				response = &http.Response{StatusCode: tt.statusCode}
+			}
+			if got := isRetryable(tt.method, response, tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindRecord(t *testing.T) {
+	records := []Record{
+		{Name: "www.example.net", RRType: "CNAME", Value: "www.example.net.edgekey.net", TTL: 3600},
+		{Name: "txt.example.net", RRType: "TXT", Value: "first", TTL: 3600},
+		{Name: "txt.example.net", RRType: "CNAME", Value: "other.example.net", TTL: 60},
+	}
+
+	tests := []struct {
+		name         string
+		recordName   string
+		rrtype       string
+		want         Record
+		wantNotFound bool
+		wantSummary  string
+		wantRequests []string
+	}{
+		{
+			name:         "by name",
+			recordName:   "www.example.net",
+			want:         records[0],
+			wantRequests: []string{"GET /v2/records"},
+		},
+		{
+			name:         "by ambiguous name",
+			recordName:   "txt.example.net",
+			wantSummary:  "Found several records with given name",
+			wantRequests: []string{"GET /v2/records"},
+		},
+		{
+			name:         "by name in other case and matching type",
+			recordName:   "WWW.Example.net.",
+			rrtype:       "cname",
+			want:         records[0],
+			wantRequests: []string{"GET /v2/records/www.example.net"},
+		},
+		{
+			name:         "by name and other type",
+			recordName:   "txt.example.net",
+			rrtype:       "CNAME",
+			want:         records[2],
+			wantRequests: []string{"GET /v2/records/txt.example.net", "GET /v2/records"},
+		},
+		{
+			name:         "missing type",
+			recordName:   "www.example.net",
+			rrtype:       "TXT",
+			wantNotFound: true,
+			wantRequests: []string{"GET /v2/records/www.example.net", "GET /v2/records"},
+		},
+		{
+			name:         "missing name",
+			recordName:   "missing.example.net",
+			rrtype:       "TXT",
+			wantNotFound: true,
+			wantRequests: []string{"GET /v2/records/missing.example.net"},
+		},
+		{
+			name:         "missing name without type",
+			recordName:   "missing.example.net",
+			wantNotFound: true,
+			wantRequests: []string{"GET /v2/records"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &recordsAPI{records: records}
+			c := newTestAPI(t, api)
+
+			got, diags := c.findRecord(context.Background(), tt.recordName, tt.rrtype)
+			if tt.wantNotFound {
+				if !isNotFound(diags) {
+					t.Errorf("findRecord() = %v, want not found", diags)
+				}
+			} else if tt.wantSummary != "" {
+				if !diags.HasError() || diags[0].Summary != tt.wantSummary {
+					t.Errorf("findRecord() = %v, want %q", diags, tt.wantSummary)
+				}
+			} else if diags.HasError() {
+				t.Fatalf("findRecord() = %v", diags)
+			} else if got != tt.want {
+				t.Errorf("findRecord() = %+v, want %+v", got, tt.want)
+			}
+			if strings.Join(api.requests, ", ") != strings.Join(tt.wantRequests, ", ") {
+				t.Errorf("requests = %v, want %v", api.requests, tt.wantRequests)
+			}
+		})
+	}
+}
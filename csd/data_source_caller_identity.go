@@ -0,0 +1,75 @@
+package csd
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCallerIdentity() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCallerIdentityRead,
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Description: "AWS account ID of the credentials used to sign API requests",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"arn": {
+				Description: "ARN of the AWS principal used to sign API requests",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"user_id": {
+				Description: "Unique ID of the AWS principal used to sign API requests",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"owner": {
+				Description: "CSD tenant the AWS principal maps to, empty if the API doesn't report it",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
+		},
+	}
+}
+
+func dataSourceCallerIdentityRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*ApiClient)
+	var diags diag.Diagnostics
+
+	identity, err := getCallerIdentity(ctx, apiClient)
+	if err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Couldn't get caller identity from AWS STS",
+			Detail:   err.Error(),
+		})
+	}
+
+	owner, diagErr := apiClient.getOwner(ctx)
+	if diagErr != nil {
+		return diagErr
+	}
+
+	if err := d.Set("account_id", identity.AccountId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("arn", identity.Arn); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("user_id", identity.UserId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("owner", owner.Owner); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the principal identifies the data source
+	d.SetId(identity.Arn)
+
+	return diags
+}
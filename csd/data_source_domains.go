@@ -0,0 +1,87 @@
+package csd
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDomains() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDomainsRead,
+		Schema: map[string]*schema.Schema{
+			"domains": {
+				Description: "List of parent domains supported by CSD, records and zone delegations can be created below them",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "FQDN of the parent domain",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"rrtypes": {
+							Description: "Types of DNS records allowed below the domain",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"min_ttl": {
+							Description: "Lowest time to life for records in seconds",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"max_ttl": {
+							Description: "Highest time to life for records in seconds",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"max_label_length": {
+							Description: "Maximum length of a single label below the domain",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
+		},
+	}
+}
+
+func dataSourceDomainsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*ApiClient)
+	var diags diag.Diagnostics
+
+	results, err := apiClient.getDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	// convert domain struct into interface mapping
+	domains := make([]interface{}, len(results))
+	for i, result := range results {
+		domain := make(map[string]interface{})
+		domain["name"] = result.Name
+		domain["rrtypes"] = result.RRTypes
+		domain["min_ttl"] = result.MinTTL
+		domain["max_ttl"] = result.MaxTTL
+		domain["max_label_length"] = result.MaxLabelLength
+
+		domains[i] = domain
+	}
+
+	if err := d.Set("domains", domains); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// set the resource ID to a hash of the result, so it only changes with it
+	d.SetId(dataSourceID(domains))
+
+	return diags
+}
@@ -0,0 +1,18 @@
+package csd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+// dataSourceID Derives a stable ID from the query inputs and results of a data source,
+// so plans stay quiet as long as nothing changed
+func dataSourceID(values ...interface{}) string {
+	// Maps are encoded with sorted keys, so equal values always give the same hash
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(encoded))
+}
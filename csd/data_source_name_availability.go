@@ -0,0 +1,189 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/net/dns/dnsmessage"
+	"strings"
+)
+
+// Possible values of the status attribute of csd_name_availability
+const (
+	nameStatusAvailable    = "available"
+	nameStatusDelegated    = "delegated"
+	nameStatusShadowed     = "shadowed"
+	nameStatusTaken        = "taken"
+	nameStatusOwnedByOther = "owned_by_other"
+)
+
+func dataSourceNameAvailability() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceNameAvailabilityRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "FQDN of the record or zone delegation to check",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"rrtype": {
+				Description: "The type of DNS record to check, any record with the name conflicts if not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"check_dns": {
+				Description: "Also ask the authoritative name servers, to find names used by someone else. Without `rrtype` or for CNAME " +
+					"records the name is used as soon as the name servers know it, even if only names below it have records.",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"resolvers": {
+				Description: "Addresses (`host` or `host:port`) of the name servers to ask if `check_dns` is enabled. " +
+					"Defaults to the authoritative name servers of the parent zone.",
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"available": {
+				Description: "Whether the name can be used",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"status": {
+				Description: "One of `available`, `delegated` (there is a zone delegation with the name), " +
+					"`shadowed` (there is a zone delegation above the name), `taken` (there is a conflicting record) " +
+					"or `owned_by_other` (the name servers serve the name, but it isn't visible to the caller)",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"reason": {
+				Description: "Explains why the name is not available",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
+		},
+	}
+}
+
+func dataSourceNameAvailabilityRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*ApiClient)
+	var diags diag.Diagnostics
+
+	name := canonicalName(d.Get("name").(string))
+	rrtype := strings.ToUpper(d.Get("rrtype").(string))
+
+	zoneDelegations, err := apiClient.getZoneDelegations(ctx)
+	if err != nil {
+		return err
+	}
+	records, err := apiClient.getRecords(ctx, RecordFilter{NameSuffix: name})
+	if err != nil {
+		return err
+	}
+
+	status, reason := nameStatusAvailable, ""
+	for _, zoneDelegation := range zoneDelegations {
+		if canonicalName(zoneDelegation.Name) == name {
+			status, reason = nameStatusDelegated, fmt.Sprintf("There is a zone delegation for %s already", zoneDelegation.Name)
+			break
+		}
+		if hasNameSuffix(name, zoneDelegation.Name) {
+			status, reason = nameStatusShadowed, fmt.Sprintf("%s is part of the delegated zone %s, manage it there", name, zoneDelegation.Name)
+		}
+	}
+	if status == nameStatusAvailable {
+		for _, record := range records {
+			if canonicalName(record.Name) != name {
+				continue
+			}
+			// CNAME records can't share their name with any other record
+			if rrtype == "" || strings.EqualFold(record.RRType, rrtype) || strings.EqualFold(record.RRType, "CNAME") || rrtype == "CNAME" {
+				status, reason = nameStatusTaken, fmt.Sprintf("There is a %s record for %s already", record.RRType, record.Name)
+				break
+			}
+		}
+	}
+	if status == nameStatusAvailable && d.Get("check_dns").(bool) {
+		var resolvers []string
+		for _, resolver := range d.Get("resolvers").([]interface{}) {
+			resolvers = append(resolvers, resolver.(string))
+		}
+		dnsStatus, dnsReason, dnsDiags := checkNameInDNS(ctx, resolvers, name, rrtype)
+		diags = append(diags, dnsDiags...)
+		if dnsStatus != "" {
+			status, reason = dnsStatus, dnsReason
+		}
+	}
+
+	if err := d.Set("available", status == nameStatusAvailable); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", status); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("reason", reason); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// set the resource ID to a hash of query and result, so it only changes with them
+	d.SetId(dataSourceID(name, rrtype, status, reason))
+
+	return diags
+}
+
+// checkNameInDNS Looks for records or delegations served for name that the API didn't tell us about.
+// DNS failures only lead to warnings, as the API already had its say.
+func checkNameInDNS(ctx context.Context, resolvers []string, name string, rrtype string) (string, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(resolvers) == 0 {
+		var err error
+		if resolvers, err = findAuthoritativeServers(ctx, name); err != nil {
+			return "", "", append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Couldn't check name in DNS",
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	// A CNAME conflicts with any other record. Without a type or for a new CNAME any record conflicts, so
+	// instead of querying every type an authoritative answer without records tells the name exists (NODATA),
+	// while names without any records give NXDOMAIN.
+	types := []string{"CNAME"}
+	if rrtype != "" && rrtype != "CNAME" && isQueryableType(rrtype) {
+		types = append(types, rrtype)
+	}
+
+	for _, server := range resolvers {
+		for _, qtype := range types {
+			answer, err := queryDNS(ctx, server, name, qtype)
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Couldn't check name in DNS",
+					Detail:   fmt.Sprintf("%s didn't answer: %s", server, err),
+				})
+				break
+			}
+			if answer.ReferralZone != "" {
+				return nameStatusOwnedByOther, fmt.Sprintf("%s is delegated to other name servers as part of %s", name, answer.ReferralZone), diags
+			}
+			if len(answer.Values) > 0 {
+				return nameStatusOwnedByOther, fmt.Sprintf("%s serves a %s record for %s", server, qtype, name), diags
+			}
+			if (rrtype == "" || rrtype == "CNAME") && answer.Authoritative && answer.RCode == dnsmessage.RCodeSuccess {
+				return nameStatusOwnedByOther, fmt.Sprintf("%s serves other records for %s or names below it", server, name), diags
+			}
+		}
+	}
+
+	return "", "", diags
+}
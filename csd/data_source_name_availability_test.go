@@ -0,0 +1,45 @@
+package csd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCheckNameInDNS(t *testing.T) {
+	tests := []struct {
+		name       string
+		rrtype     string
+		wantStatus string
+		wantReason string
+	}{
+		{name: "free.example.net", wantStatus: ""},
+		{name: "free.example.net", rrtype: "CNAME", wantStatus: ""},
+		{name: "alias.example.net", wantStatus: nameStatusOwnedByOther, wantReason: "serves a CNAME record"},
+		{name: "alias.example.net", rrtype: "TXT", wantStatus: nameStatusOwnedByOther, wantReason: "serves a CNAME record"},
+		// Records of types nobody asks for by name are found by the NODATA answer
+		{name: "app.example.net", wantStatus: nameStatusOwnedByOther, wantReason: "serves other records"},
+		{name: "app.example.net", rrtype: "CNAME", wantStatus: nameStatusOwnedByOther, wantReason: "serves other records"},
+		{name: "app.example.net", rrtype: "A", wantStatus: nameStatusOwnedByOther, wantReason: "serves a A record"},
+		{name: "app.example.net", rrtype: "TXT", wantStatus: ""},
+		{name: "www.example.net", wantStatus: nameStatusOwnedByOther, wantReason: "or names below it"},
+		{name: "www.shop.example.net", wantStatus: nameStatusOwnedByOther, wantReason: "delegated to other name servers as part of shop.example.net"},
+	}
+	server := newStubDNSServer(t)
+	server.set("alias.example.net", "CNAME", "target.example.org")
+	server.set("app.example.net", "A", "192.0.2.10")
+	server.set("_acme-challenge.www.example.net", "TXT", "token")
+	server.refer("shop.example.net", "ns1.shop.example.org")
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.rrtype, func(t *testing.T) {
+			status, reason, diags := checkNameInDNS(context.Background(), []string{server.address()}, tt.name, tt.rrtype)
+			if len(diags) > 0 {
+				t.Fatalf("checkNameInDNS() = %v", diags)
+			}
+			if status != tt.wantStatus || !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("checkNameInDNS() = %q, %q, want %q, %q", status, reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
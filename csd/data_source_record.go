@@ -4,14 +4,23 @@ import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
-	"time"
 )
 
 func dataSourceRecord() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceRecordRead,
 		Schema: map[string]*schema.Schema{
+			"fail_if_missing": {
+				Description: "Fail if the record doesn't exist, otherwise `exists` is `false` and all other attributes are empty",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"exists": {
+				Description: "Whether the record exists",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
 			"name": {
 				Description: "Name of the DNS record as FQDN",
 				Type:        schema.TypeString,
@@ -28,11 +37,15 @@ func dataSourceRecord() *schema.Resource {
 				Computed:    true,
 			},
 			"rrtype": {
-				Description: "The type of DNS record",
+				Description: "The type of DNS record, required if there are several records with the same name",
 				Type:        schema.TypeString,
+				Optional:    true,
 				Computed:    true,
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
+		},
 	}
 }
 
@@ -40,10 +53,16 @@ func dataSourceRecordRead(ctx context.Context, d *schema.ResourceData, m interfa
 	apiClient := m.(*ApiClient)
 	var diags diag.Diagnostics
 
-	//record, err := apiClient.curl("GET", fmt.Sprintf("/v2/record/%s_%s", name, rrtype), strings.NewReader(""))
-	record, err := apiClient.getRecord(d.Get("name").(string))
+	name := d.Get("name").(string)
+	rrtype := d.Get("rrtype").(string)
+
+	record, err := apiClient.findRecord(ctx, name, rrtype)
+	exists := true
 	if err != nil {
-		return err
+		if d.Get("fail_if_missing").(bool) || !isNotFound(err) {
+			return err
+		}
+		exists = false
 	}
 
 	// sets the response body (record object) to Terraform record data source
@@ -53,12 +72,18 @@ func dataSourceRecordRead(ctx context.Context, d *schema.ResourceData, m interfa
 	if err := d.Set("value", record.Value); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("rrtype", record.RRType); err != nil {
+	// keep rrtype as configured, it is only looked up if not set
+	if rrtype == "" {
+		if err := d.Set("rrtype", record.RRType); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("exists", exists); err != nil {
 		return diag.FromErr(err)
 	}
 
-	// always run; set the resource ID to timestamp (forces this resource to refresh during every Terraform apply)
-	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	// set the resource ID to a hash of query and result, so it only changes with them
+	d.SetId(dataSourceID(name, rrtype, record))
 
 	return diags
 }
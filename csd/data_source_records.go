@@ -4,14 +4,37 @@ import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
-	"time"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"strings"
 )
 
 func dataSourceRecords() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceRecordsRead,
 		Schema: map[string]*schema.Schema{
+			"rrtype": {
+				Description: "Only return records of this type",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"name_suffix": {
+				Description: "Only return records with this name or below it, e.g. the name of a zone",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"name_regex": {
+				Description:  "Only return records with a name matching this regular expression",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"value_regex": {
+				Description:  "Only return records with a value matching this regular expression",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
 			"records": {
 				Description: "List of configured DNS records",
 				Type:        schema.TypeList,
@@ -42,6 +65,9 @@ func dataSourceRecords() *schema.Resource {
 				},
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
+		},
 	}
 }
 
@@ -49,30 +75,42 @@ func dataSourceRecordsRead(ctx context.Context, d *schema.ResourceData, m interf
 	apiClient := m.(*ApiClient)
 	var diags diag.Diagnostics
 
-	results, err := apiClient.getRecords()
+	filter := RecordFilter{
+		RRType:     strings.ToUpper(d.Get("rrtype").(string)),
+		NameSuffix: canonicalName(d.Get("name_suffix").(string)),
+	}
+	// Regular expressions were checked by validation.StringIsValidRegExp already
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+	valueRegex := regexp.MustCompile(d.Get("value_regex").(string))
+
+	results, err := apiClient.getRecords(ctx, filter)
 	if err != nil {
 		return err
 	}
 
-	// convert record struct into interface mapping
+	// convert record struct into interface mapping, filtering on our side in case the API doesn't
 	// TODO: can we avoid this?
-	records := make([]interface{}, len(results), len(results))
-	for i, result := range results {
+	records := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		if !filter.matches(result) || !nameRegex.MatchString(result.Name) || !valueRegex.MatchString(result.Value) {
+			continue
+		}
+
 		record := make(map[string]interface{})
 		record["name"] = result.Name
 		record["rrtype"] = result.RRType
 		record["value"] = result.Value
 		record["ttl"] = result.TTL
 
-		records[i] = record
+		records = append(records, record)
 	}
 
 	if err := d.Set("records", records); err != nil {
 		return diag.FromErr(err)
 	}
 
-	// always run; set the resource ID to timestamp (forces this resource to refresh during every Terraform apply)
-	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	// set the resource ID to a hash of query and result, so it only changes with them
+	d.SetId(dataSourceID(filter, d.Get("name_regex").(string), d.Get("value_regex").(string), records))
 
 	return diags
 }
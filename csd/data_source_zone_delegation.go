@@ -4,14 +4,23 @@ import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
-	"time"
 )
 
 func dataSourceZoneDelegation() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceZoneDelegationRead,
 		Schema: map[string]*schema.Schema{
+			"fail_if_missing": {
+				Description: "Fail if the zone delegation doesn't exist, otherwise `exists` is `false` and all other attributes are empty",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"exists": {
+				Description: "Whether the zone delegation exists",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
 			"name": {
 				Description: "FQDN of the DNS zone",
 				Type:        schema.TypeString,
@@ -25,6 +34,17 @@ func dataSourceZoneDelegation() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"ds_records": {
+				Description: "DS records of the zone, formatted as `<key tag> <algorithm> <digest type> <digest>`",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
 		},
 	}
 }
@@ -36,9 +56,14 @@ func dataSourceZoneDelegationRead(ctx context.Context, d *schema.ResourceData, m
 	name := d.Get("name").(string)
 
 	//zoneDelegation, err := apiClient.curl("GET", fmt.Sprintf("/v2/zone_delegations/%s", name), strings.NewReader(""))
-	zoneDelegation, err := apiClient.getZoneDelegation(name)
+	zoneDelegation, err := apiClient.getZoneDelegation(ctx, name)
+	exists := true
 	if err != nil {
-		return err
+		if d.Get("fail_if_missing").(bool) || !isNotFound(err) {
+			return err
+		}
+		zoneDelegation = ZoneDelegation{}
+		exists = false
 	}
 
 	nameServers := make([]interface{}, len(zoneDelegation.NameServers), len(zoneDelegation.NameServers))
@@ -50,9 +75,15 @@ func dataSourceZoneDelegationRead(ctx context.Context, d *schema.ResourceData, m
 	if err := d.Set("name_servers", nameServers); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("ds_records", flattenDSRecords(zoneDelegation.DSRecords)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("exists", exists); err != nil {
+		return diag.FromErr(err)
+	}
 
-	// always run; set the resource ID to timestamp (forces this resource to refresh during every Terraform apply)
-	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	// set the resource ID to a hash of query and result, so it only changes with them
+	d.SetId(dataSourceID(name, zoneDelegation))
 
 	return diags
 }
@@ -4,14 +4,31 @@ import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
-	"time"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
 )
 
 func dataSourceZoneDelegations() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceZoneDelegationsRead,
 		Schema: map[string]*schema.Schema{
+			"name_suffix": {
+				Description: "Only return zone delegations with this name or below it",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"name_regex": {
+				Description:  "Only return zone delegations with a name matching this regular expression",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"include_records": {
+				Description: "Also list the DNS records below each zone delegation in `records`. Records below nested zone delegations are only listed for the closest one.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 			"zone_delegations": {
 				Description: "List of configured DNS zone delegations",
 				Type:        schema.TypeList,
@@ -31,10 +48,50 @@ func dataSourceZoneDelegations() *schema.Resource {
 								Type: schema.TypeString,
 							},
 						},
+						"ds_records": {
+							Description: "DS records of the zone, formatted as `<key tag> <algorithm> <digest type> <digest>`",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"records": {
+							Description: "DNS records below the zone delegation, only set if `include_records` is enabled",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Description: "Name of the DNS record as FQDN",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"value": {
+										Description: "Value of the DNS record (FQDN of Akamai Edgekey Hostname in case of CNAME)",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"ttl": {
+										Description: "Time to life for the record in seconds",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"rrtype": {
+										Description: "The type of DNS record",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+								},
+							},
+						},
 					},
 				},
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(defaultTimeout),
+		},
 	}
 }
 
@@ -42,28 +99,74 @@ func dataSourceZoneDelegationsRead(ctx context.Context, d *schema.ResourceData,
 	apiClient := m.(*ApiClient)
 	var diags diag.Diagnostics
 
-	results, err := apiClient.getZoneDelegations()
+	nameSuffix := d.Get("name_suffix").(string)
+	// Regular expression was checked by validation.StringIsValidRegExp already
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	results, err := apiClient.getZoneDelegations(ctx)
 	if err != nil {
 		return err
 	}
 
+	// Records are fetched once and assigned to the zone delegation they are part of. With nested zone
+	// delegations that is the closest one, even if it is filtered out itself.
+	recordsByZoneDelegation := make(map[string][]Record)
+	if d.Get("include_records").(bool) {
+		records, err := apiClient.getRecords(ctx, RecordFilter{NameSuffix: canonicalName(nameSuffix)})
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if name, ok := closestZoneDelegation(record.Name, results); ok {
+				recordsByZoneDelegation[name] = append(recordsByZoneDelegation[name], record)
+			}
+		}
+	}
+
 	// convert zone struct into interface mapping
 	// TODO: can we avoid this?
-	zoneDelegations := make([]interface{}, len(results), len(results))
-	for i, result := range results {
+	zoneDelegations := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		if !hasNameSuffix(result.Name, nameSuffix) || !nameRegex.MatchString(result.Name) {
+			continue
+		}
+
 		zoneDelegation := make(map[string]interface{})
 		zoneDelegation["name"] = result.Name
 		zoneDelegation["name_servers"] = result.NameServers
+		zoneDelegation["ds_records"] = flattenDSRecords(result.DSRecords)
+
+		zoneRecords := make([]interface{}, 0)
+		for _, record := range recordsByZoneDelegation[result.Name] {
+			zoneRecord := make(map[string]interface{})
+			zoneRecord["name"] = record.Name
+			zoneRecord["rrtype"] = record.RRType
+			zoneRecord["value"] = record.Value
+			zoneRecord["ttl"] = record.TTL
+			zoneRecords = append(zoneRecords, zoneRecord)
+		}
+		zoneDelegation["records"] = zoneRecords
 
-		zoneDelegations[i] = zoneDelegation
+		zoneDelegations = append(zoneDelegations, zoneDelegation)
 	}
 
 	if err := d.Set("zone_delegations", zoneDelegations); err != nil {
 		return diag.FromErr(err)
 	}
 
-	// always run; set the resource ID to timestamp (forces this resource to refresh during every Terraform apply)
-	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	// set the resource ID to a hash of query and result, so it only changes with them
+	d.SetId(dataSourceID(nameSuffix, d.Get("name_regex").(string), d.Get("include_records").(bool), zoneDelegations))
 
 	return diags
 }
+
+// closestZoneDelegation Returns the name of the longest zone delegation name is part of
+func closestZoneDelegation(name string, zoneDelegations []ZoneDelegation) (string, bool) {
+	closest, found := "", false
+	for _, zoneDelegation := range zoneDelegations {
+		if hasNameSuffix(name, zoneDelegation.Name) && (!found || len(canonicalName(zoneDelegation.Name)) > len(canonicalName(closest))) {
+			closest, found = zoneDelegation.Name, true
+		}
+	}
+	return closest, found
+}
@@ -0,0 +1,80 @@
+package csd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceZoneDelegationsIncludeRecords(t *testing.T) {
+	records := &recordsAPI{records: []Record{
+		{Name: "www.example.net", RRType: "CNAME", Value: "www.example.net.edgekey.net", TTL: 300},
+		{Name: "www.shop.example.net", RRType: "CNAME", Value: "www.shop.example.net.edgekey.net", TTL: 300},
+		{Name: "api.team.shop.example.net", RRType: "A", Value: "192.0.2.10", TTL: 300},
+	}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/zone_delegations", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]ZoneDelegation{
+			{Name: "example.net", NameServers: []string{"ns1.example.net"}},
+			{Name: "shop.example.net", NameServers: []string{"ns1.shop.example.net"}},
+			{Name: "team.shop.example.net", NameServers: []string{"ns1.team.example.net"}},
+		})
+	})
+	mux.Handle("/v2/records", records)
+	apiClient := newTestAPI(t, mux)
+
+	// The innermost zone delegation is filtered out, its records must not show up for shop.example.net
+	d := schema.TestResourceDataRaw(t, dataSourceZoneDelegations().Schema, map[string]interface{}{
+		"name_regex":      "^(shop\\.)?example\\.net$",
+		"include_records": true,
+	})
+	if diags := dataSourceZoneDelegationsRead(context.Background(), d, apiClient); diags.HasError() {
+		t.Fatalf("dataSourceZoneDelegationsRead() = %v", diags)
+	}
+
+	want := map[string][]string{
+		"example.net":      {"www.example.net"},
+		"shop.example.net": {"www.shop.example.net"},
+	}
+	zoneDelegations := d.Get("zone_delegations").([]interface{})
+	if len(zoneDelegations) != len(want) {
+		t.Fatalf("got %d zone delegations, want %d", len(zoneDelegations), len(want))
+	}
+	for _, zoneDelegation := range zoneDelegations {
+		zoneDelegation := zoneDelegation.(map[string]interface{})
+		var names []string
+		for _, record := range zoneDelegation["records"].([]interface{}) {
+			names = append(names, record.(map[string]interface{})["name"].(string))
+		}
+		name := zoneDelegation["name"].(string)
+		if len(names) != len(want[name]) || (len(names) > 0 && names[0] != want[name][0]) {
+			t.Errorf("records of %s = %v, want %v", name, names, want[name])
+		}
+	}
+}
+
+func TestClosestZoneDelegation(t *testing.T) {
+	zoneDelegations := []ZoneDelegation{{Name: "shop.example.net"}, {Name: "example.net"}, {Name: "team.shop.example.net."}}
+	tests := []struct {
+		name  string
+		want  string
+		found bool
+	}{
+		{name: "www.example.net", want: "example.net", found: true},
+		{name: "WWW.Shop.Example.net.", want: "shop.example.net", found: true},
+		{name: "api.team.shop.example.net", want: "team.shop.example.net.", found: true},
+		{name: "myshop.example.net", want: "example.net", found: true},
+		{name: "www.example.org", found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := closestZoneDelegation(tt.name, zoneDelegations)
+			if got != tt.want || found != tt.found {
+				t.Errorf("closestZoneDelegation(%q) = %q, %v, want %q, %v", tt.name, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
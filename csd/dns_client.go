@@ -0,0 +1,199 @@
+package csd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsTimeout Limits a single DNS exchange if the context doesn't have an earlier deadline
+const dnsTimeout = 5 * time.Second
+
+// dnsTypes Maps the record types we know how to verify to their wire representation
+var dnsTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"NS":    dnsmessage.TypeNS,
+	"SOA":   dnsmessage.TypeSOA,
+	"TXT":   dnsmessage.TypeTXT,
+}
+
+// isQueryableType Reports whether queryDNS supports the record type
+func isQueryableType(rrtype string) bool {
+	_, ok := dnsTypes[strings.ToUpper(rrtype)]
+	return ok
+}
+
+// queryableTypes Returns the record types queryDNS supports in alphabetical order
+func queryableTypes() []string {
+	return slices.Sorted(maps.Keys(dnsTypes))
+}
+
+// DNSAnswer holds the parts of a DNS response we care about
+type DNSAnswer struct {
+	Authoritative bool
+	RCode         dnsmessage.RCode
+	Values        []string
+	// Referral Holds the NS records of the authority section when a parent zone refers to the delegated
+	// zone ReferralZone, which is the queried name or one of its parents
+	Referral     []string
+	ReferralZone string
+}
+
+// serverAddress Adds the default DNS port to a server address if it doesn't have one
+func serverAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+// queryDNS Asks a single name server for the records of the given type, without recursion
+func queryDNS(ctx context.Context, server string, name string, rrtype string) (DNSAnswer, error) {
+	var answer DNSAnswer
+
+	qtype, ok := dnsTypes[strings.ToUpper(rrtype)]
+	if !ok {
+		return answer, fmt.Errorf("unsupported record type %q", rrtype)
+	}
+	qname, err := dnsmessage.NewName(canonicalName(name) + ".")
+	if err != nil {
+		return answer, err
+	}
+
+	id := uint16(rand.UintN(1 << 16))
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		return answer, err
+	}
+
+	response, err := exchangeDNS(ctx, "udp", serverAddress(server), packed)
+	if err != nil {
+		return answer, err
+	}
+	if response.Truncated {
+		// Retry over TCP when the answer doesn't fit into a datagram
+		if response, err = exchangeDNS(ctx, "tcp", serverAddress(server), packed); err != nil {
+			return answer, err
+		}
+	}
+	if response.ID != id {
+		return answer, fmt.Errorf("mismatching response id from %s", server)
+	}
+
+	answer.Authoritative = response.Authoritative
+	answer.RCode = response.RCode
+	for _, resource := range response.Answers {
+		if resource.Header.Type != qtype || !strings.EqualFold(resource.Header.Name.String(), qname.String()) {
+			continue
+		}
+		switch body := resource.Body.(type) {
+		case *dnsmessage.AResource:
+			answer.Values = append(answer.Values, net.IP(body.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			answer.Values = append(answer.Values, net.IP(body.AAAA[:]).String())
+		case *dnsmessage.CNAMEResource:
+			answer.Values = append(answer.Values, body.CNAME.String())
+		case *dnsmessage.NSResource:
+			answer.Values = append(answer.Values, body.NS.String())
+		case *dnsmessage.SOAResource:
+			answer.Values = append(answer.Values, fmt.Sprintf("%s %s %d", body.NS.String(), body.MBox.String(), body.Serial))
+		case *dnsmessage.TXTResource:
+			answer.Values = append(answer.Values, strings.Join(body.TXT, ""))
+		}
+	}
+	for _, resource := range response.Authorities {
+		body, ok := resource.Body.(*dnsmessage.NSResource)
+		if !ok || response.Authoritative || !hasNameSuffix(qname.String(), resource.Header.Name.String()) {
+			continue
+		}
+		answer.Referral = append(answer.Referral, body.NS.String())
+		answer.ReferralZone = canonicalName(resource.Header.Name.String())
+	}
+
+	return answer, nil
+}
+
+// exchangeDNS Sends a packed query to server and parses the response
+func exchangeDNS(ctx context.Context, network string, server string, packed []byte) (dnsmessage.Message, error) {
+	var response dnsmessage.Message
+
+	ctx, cancel := context.WithTimeout(ctx, dnsTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return response, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return response, err
+		}
+	}
+
+	buffer := make([]byte, 65535)
+	var length int
+	if network == "tcp" {
+		// DNS over TCP prefixes every message with its length
+		if _, err := conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(packed)))); err != nil {
+			return response, err
+		}
+		if _, err := conn.Write(packed); err != nil {
+			return response, err
+		}
+		if _, err := io.ReadFull(conn, buffer[:2]); err != nil {
+			return response, err
+		}
+		length = int(binary.BigEndian.Uint16(buffer[:2]))
+		if _, err := io.ReadFull(conn, buffer[:length]); err != nil {
+			return response, err
+		}
+	} else {
+		if _, err := conn.Write(packed); err != nil {
+			return response, err
+		}
+		if length, err = conn.Read(buffer); err != nil {
+			return response, err
+		}
+	}
+
+	if err := response.Unpack(buffer[:length]); err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// findAuthoritativeServers Looks up the name servers of the closest zone above name using the system resolver.
+// The name itself is skipped, as it is a record or a delegated zone within that parent zone.
+func findAuthoritativeServers(ctx context.Context, name string) ([]string, error) {
+	labels := strings.Split(canonicalName(name), ".")
+	for i := 1; i < len(labels); i++ {
+		zone := strings.Join(labels[i:], ".")
+		records, err := net.DefaultResolver.LookupNS(ctx, zone)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		var servers []string
+		for _, record := range records {
+			servers = append(servers, record.Host)
+		}
+		return servers, nil
+	}
+	return nil, fmt.Errorf("couldn't find authoritative name servers for %s", name)
+}
@@ -0,0 +1,143 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// dsDigestPattern Matches the hex encoded digest of a DS record
+var dsDigestPattern = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+// parseDSRecord Reads a DS record in presentation format, e.g. `12345 13 2 1F2E...`
+func parseDSRecord(value string) (DSRecord, error) {
+	var record DSRecord
+
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return record, fmt.Errorf("expected `<key tag> <algorithm> <digest type> <digest>`, got %q", value)
+	}
+
+	keyTag, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return record, fmt.Errorf("invalid key tag %q", fields[0])
+	}
+	algorithm, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return record, fmt.Errorf("invalid algorithm %q", fields[1])
+	}
+	digestType, err := strconv.ParseUint(fields[2], 10, 8)
+	if err != nil {
+		return record, fmt.Errorf("invalid digest type %q", fields[2])
+	}
+	// Long digests are sometimes split into several blocks
+	digest := strings.Join(fields[3:], "")
+	if !dsDigestPattern.MatchString(digest) {
+		return record, fmt.Errorf("invalid digest %q", digest)
+	}
+
+	record.KeyTag = int(keyTag)
+	record.Algorithm = int(algorithm)
+	record.DigestType = int(digestType)
+	record.Digest = strings.ToUpper(digest)
+	return record, nil
+}
+
+// String Formats the DS record in presentation format
+func (r DSRecord) String() string {
+	return fmt.Sprintf("%d %d %d %s", r.KeyTag, r.Algorithm, r.DigestType, strings.ToUpper(r.Digest))
+}
+
+// dsRecordValidator Makes sure a string attribute is a DS record in presentation format
+type dsRecordValidator struct{}
+
+func (v dsRecordValidator) Description(ctx context.Context) string {
+	return "value must be a DS record like `12345 13 2 1F2E...`"
+}
+
+func (v dsRecordValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v dsRecordValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := parseDSRecord(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid DS record", err.Error())
+	}
+}
+
+// equivalentDSRecords Keeps the DS records from state if the configuration only differs in whitespace
+// and the case of digests
+type equivalentDSRecords struct{}
+
+func (m equivalentDSRecords) Description(ctx context.Context) string {
+	return "Ignores differences in whitespace and the case of digests."
+}
+
+func (m equivalentDSRecords) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m equivalentDSRecords) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+	if req.StateValue.IsNull() || req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	var state, config []string
+	resp.Diagnostics.Append(req.StateValue.ElementsAs(ctx, &state, false)...)
+	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &config, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if equalDSRecords(state, config) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// equalDSRecords Compares two lists of DS records in presentation format
+func equalDSRecords(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		recordA, err := parseDSRecord(a[i])
+		if err != nil {
+			return false
+		}
+		recordB, err := parseDSRecord(b[i])
+		if err != nil || recordA != recordB {
+			return false
+		}
+	}
+	return true
+}
+
+// expandDSRecords Converts DS records from Terraform configuration into API objects. Without values it returns nil,
+// so the API keeps the DS records as they are, but an empty list removes all of them.
+func expandDSRecords(values []string) []DSRecord {
+	if values == nil {
+		return nil
+	}
+	records := []DSRecord{}
+	for _, value := range values {
+		// Values were checked by dsRecordValidator already
+		record, _ := parseDSRecord(value)
+		records = append(records, record)
+	}
+	return records
+}
+
+// flattenDSRecords Converts DS records from the API into Terraform values
+func flattenDSRecords(records []DSRecord) []interface{} {
+	values := make([]interface{}, len(records))
+	for i, record := range records {
+		values[i] = record.String()
+	}
+	return values
+}
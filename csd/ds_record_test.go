@@ -0,0 +1,113 @@
+package csd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseDSRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    DSRecord
+		wantErr string
+	}{
+		{
+			name:  "output of aws_route53_key_signing_key",
+			value: "12345 13 2 1F2E3D4C5B6A79881F2E3D4C5B6A79881F2E3D4C5B6A79881F2E3D4C5B6A7988",
+			want:  DSRecord{KeyTag: 12345, Algorithm: 13, DigestType: 2, Digest: "1F2E3D4C5B6A79881F2E3D4C5B6A79881F2E3D4C5B6A79881F2E3D4C5B6A7988"},
+		},
+		{
+			name:  "lower case digest split into blocks",
+			value: "  2371 8 2\t1f2e3d4c 5b6a7988 ",
+			want:  DSRecord{KeyTag: 2371, Algorithm: 8, DigestType: 2, Digest: "1F2E3D4C5B6A7988"},
+		},
+		{name: "missing digest", value: "12345 13 2", wantErr: "expected"},
+		{name: "empty", value: "", wantErr: "expected"},
+		{name: "key tag too large", value: "65536 13 2 1F2E", wantErr: "invalid key tag"},
+		{name: "negative key tag", value: "-1 13 2 1F2E", wantErr: "invalid key tag"},
+		{name: "algorithm too large", value: "12345 256 2 1F2E", wantErr: "invalid algorithm"},
+		{name: "algorithm name instead of number", value: "12345 ECDSAP256SHA256 2 1F2E", wantErr: "invalid algorithm"},
+		{name: "digest type too large", value: "12345 13 300 1F2E", wantErr: "invalid digest type"},
+		{name: "digest not hex", value: "12345 13 2 1F2G", wantErr: "invalid digest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDSRecord(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseDSRecord() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDSRecord() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseDSRecord() = %+v, want %+v", got, tt.want)
+			}
+			// Formatting and parsing again must not change anything
+			if again, err := parseDSRecord(got.String()); err != nil || again != got {
+				t.Errorf("parseDSRecord(%q) = %+v, %v", got.String(), again, err)
+			}
+		})
+	}
+}
+
+func TestEqualDSRecords(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []string
+		want bool
+	}{
+		{name: "both empty", want: true},
+		{name: "whitespace and case", a: []string{"12345 13 2 1F2E"}, b: []string{" 12345  13 2 1f2e"}, want: true},
+		{name: "other digest", a: []string{"12345 13 2 1F2E"}, b: []string{"12345 13 2 1F2F"}, want: false},
+		{name: "other order", a: []string{"1 13 2 AA", "2 13 2 BB"}, b: []string{"2 13 2 BB", "1 13 2 AA"}, want: false},
+		{name: "more records", a: []string{"1 13 2 AA"}, b: []string{"1 13 2 AA", "2 13 2 BB"}, want: false},
+		{name: "invalid records", a: []string{"invalid"}, b: []string{"invalid"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := equalDSRecords(tt.a, tt.b); got != tt.want {
+				t.Errorf("equalDSRecords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandDSRecordsPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{
+			name: "not configured",
+			want: `{"name":"app.example.net","name_servers":[]}`,
+		},
+		{
+			name:   "removing all",
+			values: []string{},
+			want:   `{"name":"app.example.net","name_servers":[],"ds_records":[]}`,
+		},
+		{
+			name:   "one record",
+			values: []string{"12345 13 2 1f2e"},
+			want:   `{"name":"app.example.net","name_servers":[],"ds_records":[{"key_tag":12345,"algorithm":13,"digest_type":2,"digest":"1F2E"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := json.Marshal(ZoneDelegation{Name: "app.example.net", NameServers: []string{}, DSRecords: expandDSRecords(tt.values)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(payload) != tt.want {
+				t.Errorf("payload = %s, want %s", payload, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package csd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/ephemeral/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// txtRecordDefaultTTL Keeps resolvers from caching verification challenges longer than necessary
+const txtRecordDefaultTTL = 60
+
+// txtRecordPrivateKey Is the key of the private data that remembers the record to delete on close
+const txtRecordPrivateKey = "name"
+
+var (
+	_ ephemeral.EphemeralResource              = &txtRecordEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &txtRecordEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &txtRecordEphemeralResource{}
+)
+
+type txtRecordEphemeralResource struct {
+	apiClient *ApiClient
+}
+
+type txtRecordEphemeralResourceModel struct {
+	Name     types.String   `tfsdk:"name"`
+	Value    types.String   `tfsdk:"value"`
+	TTL      types.Int64    `tfsdk:"ttl"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func NewTXTRecordEphemeralResource() ephemeral.EphemeralResource {
+	return &txtRecordEphemeralResource{}
+}
+
+func (r *txtRecordEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_txt_record"
+}
+
+func (r *txtRecordEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a TXT record for the duration of a Terraform run and deletes it afterwards, " +
+			"e.g. for `_acme-challenge` or domain verification tokens. Neither the record nor its value end up in state.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the DNS record as FQDN",
+				Required:            true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Value of the TXT record",
+				Required:            true,
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "Time to life for the record in seconds, defaults to 60",
+				Optional:            true,
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (r *txtRecordEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *txtRecordEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var config txtRecordEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := config.Timeouts.Open(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	record := Record{
+		Name:   canonicalName(config.Name.ValueString()),
+		RRType: "TXT",
+		Value:  config.Value.ValueString(),
+		TTL:    txtRecordDefaultTTL,
+	}
+	if !config.TTL.IsNull() {
+		record.TTL = int(config.TTL.ValueInt64())
+	}
+
+	result, err := r.apiClient.createRecord(ctx, record)
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Close only gets the private data, so remember which record to delete
+	name, _ := json.Marshal(result.Name)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, txtRecordPrivateKey, name)...)
+
+	config.TTL = types.Int64Value(int64(result.TTL))
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &config)...)
+}
+
+func (r *txtRecordEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	data, diags := req.Private.GetKey(ctx, txtRecordPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || data == nil {
+		return
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		resp.Diagnostics.AddError("Couldn't read private data of TXT record", err.Error())
+		return
+	}
+
+	// Close has no timeouts of its own
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.deleteRecord(ctx, name))...)
+}
@@ -0,0 +1,50 @@
+package csd
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &edgekeyHostnameFunction{}
+
+type edgekeyHostnameFunction struct{}
+
+func NewEdgekeyHostnameFunction() function.Function {
+	return &edgekeyHostnameFunction{}
+}
+
+func (f *edgekeyHostnameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "edgekey_hostname"
+}
+
+func (f *edgekeyHostnameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Derives the Akamai edge hostname for a domain name",
+		MarkdownDescription: "Appends `" + edgekeyDomain + "` to the normalized domain name, e.g. `www.example.net` becomes " +
+			"`www.example.net." + edgekeyDomain + "`, the value of a `CNAME` record pointing to Akamai. " +
+			"Edge hostnames are returned normalized but otherwise unchanged.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "fqdn",
+				MarkdownDescription: "Domain name served by Akamai",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *edgekeyHostnameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var fqdn string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &fqdn))
+	if resp.Error != nil {
+		return
+	}
+
+	if canonicalName(fqdn) == "" {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, "The domain name must not be empty"))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, edgekeyHostname(fqdn)))
+}
@@ -0,0 +1,45 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &fqdnFitsCertificateFunction{}
+
+type fqdnFitsCertificateFunction struct{}
+
+func NewFQDNFitsCertificateFunction() function.Function {
+	return &fqdnFitsCertificateFunction{}
+}
+
+func (f *fqdnFitsCertificateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "fqdn_fits_certificate"
+}
+
+func (f *fqdnFitsCertificateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks if a TLS certificate can be issued for a domain name",
+		MarkdownDescription: fmt.Sprintf("Returns `true` if the normalized domain name doesn't exceed %d characters "+
+			"including the final dot, e.g. to validate names in a precondition.", certificateNameMaxLength),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "fqdn",
+				MarkdownDescription: "Domain name to check",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *fqdnFitsCertificateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var fqdn string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &fqdn))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, fitsCertificate(fqdn)))
+}
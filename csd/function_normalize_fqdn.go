@@ -0,0 +1,44 @@
+package csd
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &normalizeFQDNFunction{}
+
+type normalizeFQDNFunction struct{}
+
+func NewNormalizeFQDNFunction() function.Function {
+	return &normalizeFQDNFunction{}
+}
+
+func (f *normalizeFQDNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_fqdn"
+}
+
+func (f *normalizeFQDNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Normalizes a domain name like csd_record does",
+		MarkdownDescription: "Lowercases the domain name and strips surrounding whitespace and the trailing dot, " +
+			"e.g. `Sample-App.Example.net.` becomes `sample-app.example.net`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "fqdn",
+				MarkdownDescription: "Domain name to normalize",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *normalizeFQDNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var fqdn string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &fqdn))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, canonicalName(fqdn)))
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 func getCreds(p string, r string) (aws.Credentials, error) {
@@ -26,3 +28,29 @@ func getCreds(p string, r string) (aws.Credentials, error) {
 
 	return creds, nil
 }
+
+// CallerIdentity describes the AWS principal whose credentials sign the API requests
+type CallerIdentity struct {
+	AccountId string
+	Arn       string
+	UserId    string
+}
+
+// getCallerIdentity asks AWS STS who the given credentials belong to
+func getCallerIdentity(ctx context.Context, c *ApiClient) (CallerIdentity, error) {
+	var identity CallerIdentity
+
+	client := sts.New(sts.Options{
+		Region:      c.Region,
+		Credentials: credentials.NewStaticCredentialsProvider(c.AccessKeyId, c.SecretAccessKey, c.SessionToken),
+	})
+	output, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return identity, err
+	}
+
+	identity.AccountId = aws.ToString(output.Account)
+	identity.Arn = aws.ToString(output.Arn)
+	identity.UserId = aws.ToString(output.UserId)
+	return identity, nil
+}
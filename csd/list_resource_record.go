@@ -0,0 +1,126 @@
+package csd
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &recordListResource{}
+	_ list.ListResourceWithConfigure = &recordListResource{}
+)
+
+type recordListResource struct {
+	apiClient *ApiClient
+}
+
+type recordListResourceModel struct {
+	RRType     types.String `tfsdk:"rrtype"`
+	NameSuffix types.String `tfsdk:"name_suffix"`
+	NameRegex  types.String `tfsdk:"name_regex"`
+	ValueRegex types.String `tfsdk:"value_regex"`
+}
+
+func NewRecordListResource() list.ListResource {
+	return &recordListResource{}
+}
+
+func (r *recordListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_record"
+}
+
+func (r *recordListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists DNS records, e.g. to generate `import` blocks and configuration with `terraform query`.",
+		Attributes: map[string]schema.Attribute{
+			"rrtype": schema.StringAttribute{
+				MarkdownDescription: "Only list records of this type",
+				Optional:            true,
+			},
+			"name_suffix": schema.StringAttribute{
+				MarkdownDescription: "Only list records with this name or below it, e.g. the name of a zone",
+				Optional:            true,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list records with a name matching this regular expression",
+				Optional:            true,
+				Validators: []validator.String{
+					regexValidator{},
+				},
+			},
+			"value_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list records with a value matching this regular expression",
+				Optional:            true,
+				Validators: []validator.String{
+					regexValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (r *recordListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *recordListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config recordListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	filter := RecordFilter{
+		RRType:     strings.ToUpper(config.RRType.ValueString()),
+		NameSuffix: canonicalName(config.NameSuffix.ValueString()),
+	}
+	// Regular expressions were checked by regexValidator already
+	nameRegex := regexp.MustCompile(config.NameRegex.ValueString())
+	valueRegex := regexp.MustCompile(config.ValueRegex.ValueString())
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	records, err := r.apiClient.getRecords(ctx, filter)
+	cancel()
+	if err != nil {
+		stream.Results = list.ListResultsStreamDiagnostics(frameworkDiagnostics(err))
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+		for _, record := range records {
+			// Filtering on our side in case the API doesn't
+			if !filter.matches(record) || !nameRegex.MatchString(record.Name) || !valueRegex.MatchString(record.Value) {
+				continue
+			}
+			if req.Limit > 0 && count >= req.Limit {
+				return
+			}
+			count++
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = record.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, newRecordIdentity(record))...)
+			if req.IncludeResource {
+				// Same attributes as after an import, the rest is left to the defaults of the resource
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("id"), canonicalName(record.Name))...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("name"), record.Name)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("rrtype"), record.RRType)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("value"), record.Value)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("ttl"), record.TTL)...)
+			}
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
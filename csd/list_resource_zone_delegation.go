@@ -0,0 +1,112 @@
+package csd
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ list.ListResource              = &zoneDelegationListResource{}
+	_ list.ListResourceWithConfigure = &zoneDelegationListResource{}
+)
+
+type zoneDelegationListResource struct {
+	apiClient *ApiClient
+}
+
+type zoneDelegationListResourceModel struct {
+	NameSuffix types.String `tfsdk:"name_suffix"`
+	NameRegex  types.String `tfsdk:"name_regex"`
+}
+
+func NewZoneDelegationListResource() list.ListResource {
+	return &zoneDelegationListResource{}
+}
+
+func (r *zoneDelegationListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_delegation"
+}
+
+func (r *zoneDelegationListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists zone delegations, e.g. to generate `import` blocks and configuration with `terraform query`.",
+		Attributes: map[string]schema.Attribute{
+			"name_suffix": schema.StringAttribute{
+				MarkdownDescription: "Only list zone delegations with this name or below it",
+				Optional:            true,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list zone delegations with a name matching this regular expression",
+				Optional:            true,
+				Validators: []validator.String{
+					regexValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (r *zoneDelegationListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *zoneDelegationListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config zoneDelegationListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	nameSuffix := config.NameSuffix.ValueString()
+	// Regular expression was checked by regexValidator already
+	nameRegex := regexp.MustCompile(config.NameRegex.ValueString())
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	zoneDelegations, err := r.apiClient.getZoneDelegations(ctx)
+	cancel()
+	if err != nil {
+		stream.Results = list.ListResultsStreamDiagnostics(frameworkDiagnostics(err))
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+		for _, zoneDelegation := range zoneDelegations {
+			if !hasNameSuffix(zoneDelegation.Name, nameSuffix) || !nameRegex.MatchString(zoneDelegation.Name) {
+				continue
+			}
+			if req.Limit > 0 && count >= req.Limit {
+				return
+			}
+			count++
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = zoneDelegation.Name
+			result.Diagnostics.Append(result.Identity.Set(ctx, newZoneDelegationIdentity(zoneDelegation.Name))...)
+			if req.IncludeResource {
+				// Same attributes as after an import, generated configuration keeps the deletion protection on
+				model := zoneDelegationResourceModel{
+					NameServers: types.ListNull(types.StringType),
+					DSRecords:   types.ListNull(types.StringType),
+				}
+				result.Diagnostics.Append(model.setZoneDelegation(ctx, zoneDelegation)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("id"), zoneDelegation.Name)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("name"), model.Name)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("name_servers"), model.NameServers)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("ds_records"), model.DSRecords)...)
+				result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root("deletion_protection"), true)...)
+			}
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Possible values of the name_server_check attribute
+const (
+	nameServerCheckOff   = "off"
+	nameServerCheckWarn  = "warn"
+	nameServerCheckError = "error"
+)
+
+// checkNameServers Queries every name server for SOA and NS of the zone and describes each problem found:
+// servers that are unreachable, not authoritative, or disagree with the others or the configuration
+func checkNameServers(ctx context.Context, zoneDelegation ZoneDelegation) []string {
+	var problems []string
+
+	var expected []string
+	for _, server := range zoneDelegation.NameServers {
+		expected = append(expected, canonicalName(server))
+	}
+	slices.Sort(expected)
+
+	soaByServer := map[string]string{}
+	for _, server := range zoneDelegation.NameServers {
+		soa, err := queryDNS(ctx, server, zoneDelegation.Name, "SOA")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s is unreachable: %s", server, err))
+			continue
+		}
+		if !soa.Authoritative || soa.RCode != dnsmessage.RCodeSuccess || len(soa.Values) == 0 {
+			problems = append(problems, fmt.Sprintf("%s is not authoritative for %s (%s)", server, zoneDelegation.Name, soa.RCode))
+			continue
+		}
+		soaByServer[server] = soa.Values[0]
+
+		ns, err := queryDNS(ctx, server, zoneDelegation.Name, "NS")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s is unreachable: %s", server, err))
+			continue
+		}
+		var actual []string
+		for _, value := range ns.Values {
+			actual = append(actual, canonicalName(value))
+		}
+		slices.Sort(actual)
+		if !slices.Equal(actual, expected) {
+			problems = append(problems, fmt.Sprintf("%s serves the NS set [%s] instead of [%s]", server, strings.Join(actual, ", "), strings.Join(expected, ", ")))
+		}
+	}
+
+	// All authoritative servers of a zone should serve the same SOA record
+	var soas []string
+	for _, soa := range soaByServer {
+		soas = append(soas, soa)
+	}
+	slices.Sort(soas)
+	if len(slices.Compact(soas)) > 1 {
+		for _, server := range zoneDelegation.NameServers {
+			if soa, ok := soaByServer[server]; ok {
+				problems = append(problems, fmt.Sprintf("%s serves a different SOA record: %s", server, soa))
+			}
+		}
+	}
+
+	return problems
+}
+
+// nameServerCheckDiagnostics Runs checkNameServers and reports the problems with the configured severity
+func nameServerCheckDiagnostics(ctx context.Context, mode string, zoneDelegation ZoneDelegation) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if mode == "" || mode == nameServerCheckOff {
+		return diags
+	}
+	severity := diag.Warning
+	if mode == nameServerCheckError {
+		severity = diag.Error
+	}
+
+	for _, problem := range checkNameServers(ctx, zoneDelegation) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: severity,
+			Summary:  fmt.Sprintf("Name servers of %s don't serve the zone properly", zoneDelegation.Name),
+			Detail:   problem,
+		})
+	}
+
+	return diags
+}
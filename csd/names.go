@@ -0,0 +1,48 @@
+package csd
+
+import (
+	"strings"
+)
+
+// canonicalName Lowercases a domain name and strips the trailing dot
+func canonicalName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// hasNameSuffix Reports whether name equals suffix or is a subdomain of it, comparing whole labels
+func hasNameSuffix(name string, suffix string) bool {
+	name = canonicalName(name)
+	suffix = canonicalName(suffix)
+	return suffix == "" || name == suffix || strings.HasSuffix(name, "."+suffix)
+}
+
+// canonicalValue Normalizes record values so DNS answers can be compared with the configuration
+func canonicalValue(rrtype string, value string) string {
+	switch strings.ToUpper(rrtype) {
+	case "CNAME", "NS":
+		return canonicalName(value)
+	case "TXT":
+		return strings.Trim(value, `"`)
+	}
+	return strings.TrimSpace(value)
+}
+
+// certificateNameMaxLength Is the longest FQDN, including the final dot, a TLS certificate can be issued for
+const certificateNameMaxLength = 64
+
+// edgekeyDomain Is the domain of Akamai edge hostnames CNAME records of Akamai properties point to
+const edgekeyDomain = "edgekey.net"
+
+// fitsCertificate Reports whether a TLS certificate can be issued for name
+func fitsCertificate(name string) bool {
+	return len(canonicalName(name)+".") <= certificateNameMaxLength
+}
+
+// edgekeyHostname Derives the Akamai edge hostname for name, edge hostnames are returned unchanged
+func edgekeyHostname(name string) string {
+	name = canonicalName(name)
+	if hasNameSuffix(name, edgekeyDomain) {
+		return name
+	}
+	return name + "." + edgekeyDomain
+}
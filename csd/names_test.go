@@ -0,0 +1,72 @@
+package csd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "www.example.net", want: "www.example.net"},
+		{name: "WWW.Example.NET.", want: "www.example.net"},
+		{name: " www.example.net. ", want: "www.example.net"},
+		{name: ".", want: ""},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalName(tt.name); got != tt.want {
+				t.Errorf("canonicalName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitsCertificate(t *testing.T) {
+	// nameOfLength Returns a name of length characters, not counting a trailing dot
+	nameOfLength := func(length int) string {
+		return strings.Repeat("a", length-len(".example.net")) + ".example.net"
+	}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "www.example.net", want: true},
+		{name: nameOfLength(63), want: true},
+		{name: nameOfLength(63) + ".", want: true},
+		{name: strings.ToUpper(nameOfLength(63)), want: true},
+		{name: nameOfLength(64), want: false},
+		{name: nameOfLength(64) + ".", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fitsCertificate(tt.name); got != tt.want {
+				t.Errorf("fitsCertificate(%q) with %d characters = %v, want %v", tt.name, len(tt.name), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEdgekeyHostname(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "www.example.net", want: "www.example.net.edgekey.net"},
+		{name: "WWW.Example.net.", want: "www.example.net.edgekey.net"},
+		{name: "www.example.net.edgekey.net", want: "www.example.net.edgekey.net"},
+		{name: "www.example.net.EdgeKey.net.", want: "www.example.net.edgekey.net"},
+		{name: "www.notedgekey.net", want: "www.notedgekey.net.edgekey.net"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := edgekeyHostname(tt.name); got != tt.want {
+				t.Errorf("edgekeyHostname(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package csd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func init() {
@@ -24,6 +26,9 @@ func init() {
 	// }
 }
 
+// defaultTimeout Limits API operations including retries, resources allow overriding it with a timeouts block
+const defaultTimeout = 2 * time.Minute
+
 func New(version string, commit string) func() *schema.Provider {
 	return func() *schema.Provider {
 		provider := &schema.Provider{
@@ -42,16 +47,34 @@ func New(version string, commit string) func() *schema.Provider {
 					Description: "The region where AWS operations will take place. Examples\n" +
 						"are us-east-1, us-west-2, etc.",
 				},
+				"route53_endpoint": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Custom Route53 API endpoint for `csd_managed_zone`, e.g. of a local stand-in for tests",
+				},
+				"max_concurrent_requests": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Maximum number of API requests in flight at the same time, shared by all resources and data sources. Not limited by default.",
+				},
+				"requests_per_second": {
+					Type:         schema.TypeFloat,
+					Optional:     true,
+					ValidateFunc: validation.FloatAtLeast(0.1),
+					Description:  "Maximum number of API requests per second, shared by all resources and data sources. Not limited by default.",
+				},
 			},
-			ResourcesMap: map[string]*schema.Resource{
-				"csd_zone_delegation": resourceZoneDelegation(),
-				"csd_record":          resourceRecord(),
-			},
+			// Resources are served by the framework provider, see NewFramework
+			ResourcesMap: map[string]*schema.Resource{},
 			DataSourcesMap: map[string]*schema.Resource{
-				"csd_zone_delegations": dataSourceZoneDelegations(),
-				"csd_zone_delegation":  dataSourceZoneDelegation(),
-				"csd_records":          dataSourceRecords(),
-				"csd_record":           dataSourceRecord(),
+				"csd_zone_delegations":  dataSourceZoneDelegations(),
+				"csd_zone_delegation":   dataSourceZoneDelegation(),
+				"csd_records":           dataSourceRecords(),
+				"csd_record":            dataSourceRecord(),
+				"csd_domains":           dataSourceDomains(),
+				"csd_caller_identity":   dataSourceCallerIdentity(),
+				"csd_name_availability": dataSourceNameAvailability(),
 			},
 		}
 
@@ -67,37 +90,50 @@ func configure(version string, commit string, p *schema.Provider) func(context.C
 		// Setup a User-Agent for the API client
 		userAgent := p.UserAgent("terraform-provider-csd", fmt.Sprintf("%s (%s)", version, commit))
 
-		// AWS credentials will be grabbed from environment variables or from ~/.aws/credentials
+		profile := d.Get("profile").(string)
+		apiClient, diags := newApiClient(profile, d.Get("region").(string), d.Get("route53_endpoint").(string), userAgent)
+		apiClient.limiter = sharedRequestLimiter(profile, apiClient, d.Get("max_concurrent_requests").(int), d.Get("requests_per_second").(float64))
 
-		// Warning or errors can be collected in a slice type
-		var diags diag.Diagnostics
-
-		creds, err := getCreds(d.Get("profile").(string), d.Get("region").(string))
-		if err != nil {
-			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Error,
-				Summary:  "Unable to find authentication info for AWS",
-				Detail:   "Please configure your AWS credentials at ~/.aws/credentials or as enviromental variables.",
-			})
+		// Test the connection to find out if credentials are valid and endpoint is working
+		ctx, cancel := context.WithTimeout(c, defaultTimeout)
+		defer cancel()
+		if _, err := apiClient.getZoneDelegations(ctx); err != nil {
+			diags = append(diags, err...)
 		}
 
-		awsAccessKeyId := creds.AccessKeyID
-		awsSecretAccessKey := creds.SecretAccessKey
-		awsSessionToken := creds.SessionToken
+		return apiClient, diags
+	}
+}
+
+// newApiClient Creates an API client with the AWS credentials for the given profile and region
+func newApiClient(profile string, region string, route53Endpoint string, userAgent string) (*ApiClient, diag.Diagnostics) {
+	// AWS credentials will be grabbed from environment variables or from ~/.aws/credentials
 
-		// Create an API Client that holds the credentials and convenience function for HTTP communication
-		apiClient := ApiClient{
-			AccessKeyId:     awsAccessKeyId,
-			SecretAccessKey: awsSecretAccessKey,
-			SessionToken:    awsSessionToken,
-			UserAgent:       userAgent,
-		}
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
 
-		// Test the connection to find out if credentials are valid and endpoint is working
-		if _, err := apiClient.getZoneDelegations(); err != nil {
-			diags = append(diags, err...)
-		}
+	creds, err := getCreds(profile, region)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to find authentication info for AWS",
+			Detail:   "Please configure your AWS credentials at ~/.aws/credentials or as enviromental variables.",
+		})
+	}
 
-		return &apiClient, diags
+	awsAccessKeyId := creds.AccessKeyID
+	awsSecretAccessKey := creds.SecretAccessKey
+	awsSessionToken := creds.SessionToken
+
+	// Create an API Client that holds the credentials and convenience function for HTTP communication
+	apiClient := ApiClient{
+		AccessKeyId:     awsAccessKeyId,
+		SecretAccessKey: awsSecretAccessKey,
+		SessionToken:    awsSessionToken,
+		Region:          region,
+		Route53Endpoint: route53Endpoint,
+		UserAgent:       userAgent,
 	}
+
+	return &apiClient, diags
 }
@@ -0,0 +1,201 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// frameworkProvider Serves everything built on terraform-plugin-framework, it is muxed with the SDKv2 provider
+// from New. Both have to share the same provider schema.
+var (
+	_ provider.ProviderWithFunctions          = &frameworkProvider{}
+	_ provider.ProviderWithEphemeralResources = &frameworkProvider{}
+	_ provider.ProviderWithListResources      = &frameworkProvider{}
+)
+
+type frameworkProvider struct {
+	version string
+	commit  string
+}
+
+type frameworkProviderModel struct {
+	Profile               types.String  `tfsdk:"profile"`
+	Region                types.String  `tfsdk:"region"`
+	Route53Endpoint       types.String  `tfsdk:"route53_endpoint"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+}
+
+func NewFramework(version string, commit string) func() provider.Provider {
+	return func() provider.Provider {
+		return &frameworkProvider{
+			version: version,
+			commit:  commit,
+		}
+	}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "csd"
+	resp.Version = p.version
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "The profile for API operations. If not set, the default profile\n" +
+					"created with `aws configure` will be used.",
+				Optional: true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "The region where AWS operations will take place. Examples\n" +
+					"are us-east-1, us-west-2, etc.",
+				Optional: true,
+			},
+			"route53_endpoint": schema.StringAttribute{
+				MarkdownDescription: "Custom Route53 API endpoint for `csd_managed_zone`, e.g. of a local stand-in for tests",
+				Optional:            true,
+			},
+			// Validated by the SDKv2 provider only, the mux would report every error twice otherwise
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of API requests in flight at the same time, shared by all resources and data sources. Not limited by default.",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of API requests per second, shared by all resources and data sources. Not limited by default.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// Configure Creates the API client, the connection test is left to the SDKv2 provider
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config frameworkProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Same default as in the SDKv2 provider schema
+	region := "eu-central-1"
+	if !config.Region.IsNull() {
+		region = config.Region.ValueString()
+	}
+
+	userAgent := fmt.Sprintf("Terraform/%s (+https://www.terraform.io) terraform-provider-csd/%s (%s)", req.TerraformVersion, p.version, p.commit)
+	apiClient, diags := newApiClient(config.Profile.ValueString(), region, config.Route53Endpoint.ValueString(), userAgent)
+	apiClient.limiter = sharedRequestLimiter(config.Profile.ValueString(), apiClient, int(config.MaxConcurrentRequests.ValueInt64()), config.RequestsPerSecond.ValueFloat64())
+	resp.Diagnostics.Append(frameworkDiagnostics(diags)...)
+
+	resp.DataSourceData = apiClient
+	resp.ResourceData = apiClient
+	resp.EphemeralResourceData = apiClient
+	resp.ListResourceData = apiClient
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewRecordResource,
+		NewZoneDelegationResource,
+		NewZoneResource,
+		NewCertificateValidationRecordsResource,
+		NewManagedZoneResource,
+		NewRecordSetResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{}
+}
+
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewTXTRecordEphemeralResource,
+	}
+}
+
+func (p *frameworkProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewRecordListResource,
+		NewZoneDelegationListResource,
+	}
+}
+
+func (p *frameworkProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewNormalizeFQDNFunction,
+		NewFQDNFitsCertificateFunction,
+		NewEdgekeyHostnameFunction,
+	}
+}
+
+// frameworkDiagnostics Converts diagnostics of the API client into framework diagnostics
+func frameworkDiagnostics(diags diag.Diagnostics) fwdiag.Diagnostics {
+	var result fwdiag.Diagnostics
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			result.AddError(d.Summary, d.Detail)
+		} else {
+			result.AddWarning(d.Summary, d.Detail)
+		}
+	}
+	return result
+}
+
+// apiClientFromProviderData Hands the API client from the provider to resources and data sources
+func apiClientFromProviderData(providerData any, diags *fwdiag.Diagnostics) *ApiClient {
+	// Provider data is not available during validation, before the provider is configured
+	if providerData == nil {
+		return nil
+	}
+	apiClient, ok := providerData.(*ApiClient)
+	if !ok {
+		diags.AddError("Unexpected provider data", fmt.Sprintf("Expected *ApiClient, got %T", providerData))
+		return nil
+	}
+	return apiClient
+}
+
+// keepEquivalent Keeps the planned or prior value if the API returns an equivalent one in a different
+// form, e.g. another case. Terraform rejects results that differ from the plan.
+func keepEquivalent(current types.String, value string, equivalent func(string, string) bool) types.String {
+	if !current.IsNull() && !current.IsUnknown() && equivalent(current.ValueString(), value) {
+		return current
+	}
+	return types.StringValue(value)
+}
+
+// regexValidator Makes sure a string attribute is a valid regular expression
+type regexValidator struct{}
+
+func (v regexValidator) Description(ctx context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid regular expression", err.Error())
+	}
+}
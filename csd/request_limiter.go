@@ -0,0 +1,136 @@
+package csd
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// requestLimiter Bounds the requests of an API client by a semaphore for the requests in flight and a token bucket
+// for the request rate. A nil limiter doesn't limit anything.
+type requestLimiter struct {
+	slots chan struct{}
+
+	mutex     sync.Mutex
+	perSecond float64
+	tokens    float64
+	updated   time.Time
+}
+
+// requestLimiterKey Identifies the provider configuration a limiter belongs to. Provider aliases with other
+// credentials, regions or endpoints get limiters of their own, even if their limits are the same.
+type requestLimiterKey struct {
+	profile         string
+	region          string
+	accessKeyId     string
+	route53Endpoint string
+	maxConcurrent   int
+	perSecond       float64
+}
+
+var (
+	requestLimitersMutex sync.Mutex
+	requestLimiters      = map[requestLimiterKey]*requestLimiter{}
+)
+
+// sharedRequestLimiter Returns the limiter for the given settings of the API client, zero stands for no limit. The
+// SDKv2 and the framework provider configure their own API clients from the same provider configuration in the same
+// process, so they have to share one limiter.
+func sharedRequestLimiter(profile string, c *ApiClient, maxConcurrent int, perSecond float64) *requestLimiter {
+	if maxConcurrent <= 0 && perSecond <= 0 {
+		return nil
+	}
+
+	requestLimitersMutex.Lock()
+	defer requestLimitersMutex.Unlock()
+
+	key := requestLimiterKey{
+		profile:         profile,
+		region:          c.Region,
+		accessKeyId:     c.AccessKeyId,
+		route53Endpoint: c.Route53Endpoint,
+		maxConcurrent:   maxConcurrent,
+		perSecond:       perSecond,
+	}
+	if limiter, ok := requestLimiters[key]; ok {
+		return limiter
+	}
+	limiter := &requestLimiter{}
+	if maxConcurrent > 0 {
+		limiter.slots = make(chan struct{}, maxConcurrent)
+	}
+	if perSecond > 0 {
+		limiter.perSecond = perSecond
+		limiter.tokens = limiter.burst()
+		limiter.updated = time.Now()
+	}
+	requestLimiters[key] = limiter
+	return limiter
+}
+
+// burst Is the capacity of the token bucket, it allows the requests of one second at once
+func (l *requestLimiter) burst() float64 {
+	return max(1, l.perSecond)
+}
+
+// acquire Waits for a token and a free slot, the returned function frees the slot again
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if err := l.take(ctx); err != nil {
+		return nil, err
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-l.slots })
+	}, nil
+}
+
+// take Removes a token from the bucket, waiting until it refilled enough if necessary
+func (l *requestLimiter) take(ctx context.Context) error {
+	if l.perSecond <= 0 {
+		return nil
+	}
+
+	for {
+		l.mutex.Lock()
+		now := time.Now()
+		l.tokens = min(l.burst(), l.tokens+now.Sub(l.updated).Seconds()*l.perSecond)
+		l.updated = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mutex.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.perSecond * float64(time.Second))
+		l.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// limitedBody Keeps the slot of a request until its response body is closed
+type limitedBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b limitedBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
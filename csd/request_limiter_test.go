@@ -0,0 +1,133 @@
+package csd
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// resetRequestLimiters Gives the test its own limiters, as they are shared across the process
+func resetRequestLimiters(t *testing.T) {
+	requestLimitersMutex.Lock()
+	defer requestLimitersMutex.Unlock()
+	limiters := requestLimiters
+	requestLimiters = map[requestLimiterKey]*requestLimiter{}
+	t.Cleanup(func() {
+		requestLimitersMutex.Lock()
+		defer requestLimitersMutex.Unlock()
+		requestLimiters = limiters
+	})
+}
+
+func TestSharedRequestLimiter(t *testing.T) {
+	resetRequestLimiters(t)
+	client := func(accessKeyId string, region string, route53Endpoint string) *ApiClient {
+		return &ApiClient{AccessKeyId: accessKeyId, Region: region, Route53Endpoint: route53Endpoint}
+	}
+	first := sharedRequestLimiter("default", client("AKID1", "eu-central-1", ""), 4, 10)
+
+	tests := []struct {
+		name          string
+		profile       string
+		client        *ApiClient
+		maxConcurrent int
+		perSecond     float64
+		wantShared    bool
+		wantNil       bool
+	}{
+		{name: "same configuration", profile: "default", client: client("AKID1", "eu-central-1", ""), maxConcurrent: 4, perSecond: 10, wantShared: true},
+		{name: "other profile", profile: "other", client: client("AKID1", "eu-central-1", ""), maxConcurrent: 4, perSecond: 10},
+		{name: "other credentials", profile: "default", client: client("AKID2", "eu-central-1", ""), maxConcurrent: 4, perSecond: 10},
+		{name: "other region", profile: "default", client: client("AKID1", "eu-west-1", ""), maxConcurrent: 4, perSecond: 10},
+		{name: "other endpoint", profile: "default", client: client("AKID1", "eu-central-1", "http://127.0.0.1:4566"), maxConcurrent: 4, perSecond: 10},
+		{name: "other limits", profile: "default", client: client("AKID1", "eu-central-1", ""), maxConcurrent: 2, perSecond: 10},
+		{name: "no limits", profile: "default", client: client("AKID1", "eu-central-1", ""), wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sharedRequestLimiter(tt.profile, tt.client, tt.maxConcurrent, tt.perSecond)
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("sharedRequestLimiter() = %v, want nil %v", got, tt.wantNil)
+			}
+			if (got == first) != tt.wantShared {
+				t.Errorf("sharedRequestLimiter() shared = %v, want %v", got == first, tt.wantShared)
+			}
+		})
+	}
+}
+
+func TestRequestLimiterConcurrency(t *testing.T) {
+	resetRequestLimiters(t)
+	limiter := sharedRequestLimiter("default", &ApiClient{}, 2, 0)
+	ctx := context.Background()
+
+	first, err := limiter.acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The third request has to wait for a free slot
+	waiting, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(waiting); err == nil {
+		t.Fatal("acquire() succeeded with all slots taken")
+	}
+
+	// Releasing twice must not free a slot of another request
+	first()
+	first()
+	if _, err := limiter.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waiting, cancel = context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(waiting); err == nil {
+		t.Fatal("acquire() succeeded after a slot was released twice")
+	}
+}
+
+func TestRequestLimiterRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond float64
+		requests  int
+		wantMin   time.Duration
+		wantMax   time.Duration
+	}{
+		{name: "burst of one second", perSecond: 20, requests: 20, wantMax: 30 * time.Millisecond},
+		{name: "beyond the burst", perSecond: 20, requests: 24, wantMin: 150 * time.Millisecond, wantMax: 400 * time.Millisecond},
+		{name: "less than one per second", perSecond: 0.5, requests: 1, wantMax: 30 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetRequestLimiters(t)
+			limiter := sharedRequestLimiter("default", &ApiClient{}, 0, tt.perSecond)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			for range tt.requests {
+				release, err := limiter.acquire(ctx)
+				if err != nil {
+					t.Fatalf("acquire() = %v", err)
+				}
+				release()
+			}
+			if elapsed := time.Since(start); elapsed < tt.wantMin || elapsed > tt.wantMax {
+				t.Errorf("%d requests took %s, want between %s and %s", tt.requests, elapsed, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestNilRequestLimiter(t *testing.T) {
+	var limiter *requestLimiter
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -0,0 +1,502 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// certificateValidationDefaultTTL Lets a failed validation be retried with new records quickly
+const certificateValidationDefaultTTL = 60
+
+var (
+	_ resource.Resource               = &certificateValidationRecordsResource{}
+	_ resource.ResourceWithConfigure  = &certificateValidationRecordsResource{}
+	_ resource.ResourceWithModifyPlan = &certificateValidationRecordsResource{}
+)
+
+type certificateValidationRecordsResource struct {
+	apiClient *ApiClient
+}
+
+type certificateValidationRecordsResourceModel struct {
+	ID                      types.String   `tfsdk:"id"`
+	DomainValidationOptions types.Set      `tfsdk:"domain_validation_options"`
+	TTL                     types.Int64    `tfsdk:"ttl"`
+	Records                 types.Set      `tfsdk:"records"`
+	ValidationRecordFQDNs   types.List     `tfsdk:"validation_record_fqdns"`
+	WaitForPropagation      []waitModel    `tfsdk:"wait_for_propagation"`
+	Timeouts                timeouts.Value `tfsdk:"timeouts"`
+}
+
+// certificateValidationOptionModel Maps an element of domain_validation_options of aws_acm_certificate
+type certificateValidationOptionModel struct {
+	DomainName          types.String `tfsdk:"domain_name"`
+	ResourceRecordName  types.String `tfsdk:"resource_record_name"`
+	ResourceRecordType  types.String `tfsdk:"resource_record_type"`
+	ResourceRecordValue types.String `tfsdk:"resource_record_value"`
+}
+
+// certificateValidationRecordModel Maps an element of the records attribute
+type certificateValidationRecordModel struct {
+	Name   types.String `tfsdk:"name"`
+	RRType types.String `tfsdk:"rrtype"`
+	Value  types.String `tfsdk:"value"`
+	TTL    types.Int64  `tfsdk:"ttl"`
+}
+
+var certificateValidationOptionType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"domain_name":           types.StringType,
+		"resource_record_name":  types.StringType,
+		"resource_record_type":  types.StringType,
+		"resource_record_value": types.StringType,
+	},
+}
+
+var certificateValidationRecordType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":   types.StringType,
+		"rrtype": types.StringType,
+		"value":  types.StringType,
+		"ttl":    types.Int64Type,
+	},
+}
+
+func NewCertificateValidationRecordsResource() resource.Resource {
+	return &certificateValidationRecordsResource{}
+}
+
+func (r *certificateValidationRecordsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate_validation_records"
+}
+
+func (r *certificateValidationRecordsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates the DNS records to validate an ACM certificate. Records shared by several " +
+			"names of the certificate, e.g. `example.net` and `*.example.net`, are only created once.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Name of the first validation record",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain_validation_options": schema.SetAttribute{
+				MarkdownDescription: "The `domain_validation_options` of an `aws_acm_certificate`, objects with " +
+					"`domain_name`, `resource_record_name`, `resource_record_type` and `resource_record_value`",
+				ElementType: certificateValidationOptionType,
+				Required:    true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "Time to life for the records in seconds",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(certificateValidationDefaultTTL),
+			},
+			"records": schema.SetAttribute{
+				MarkdownDescription: "The validation records after removing duplicates, objects with `name`, `rrtype`, `value` and `ttl`",
+				ElementType:         certificateValidationRecordType,
+				Computed:            true,
+			},
+			"validation_record_fqdns": schema.ListAttribute{
+				MarkdownDescription: "Names of the validation records, e.g. for `aws_acm_certificate_validation`",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for_propagation": waitBlock("Wait until the authoritative name servers serve all validation records after changes"),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *certificateValidationRecordsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+// ModifyPlan Plans the deduplicated records, so records that were deleted outside of Terraform show up as changes
+func (r *certificateValidationRecordsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan certificateValidationRecordsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	records, known, diags := plan.validationRecords(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !known {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("records"), certificateValidationRecordSet(records))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("validation_record_fqdns"), certificateValidationFQDNs(records))...)
+}
+
+func (r *certificateValidationRecordsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan certificateValidationRecordsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	records, _, diags := plan.validationRecords(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The records are created as a unit, so a failure doesn't leave some of them behind
+	var changes []RecordChange
+	for _, record := range records {
+		changes = append(changes, RecordChange{Action: ChangeActionCreate, Record: record})
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, changes))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(records[0].Name)
+	plan.Records = certificateValidationRecordSet(records)
+	plan.ValidationRecordFQDNs = certificateValidationFQDNs(records)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForRecords(ctx, plan.WaitForPropagation, records)...)
+}
+
+func (r *certificateValidationRecordsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state certificateValidationRecordsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	current, diags := certificateValidationRecordsFromSet(ctx, state.Records)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Records deleted outside of Terraform are dropped here and created again by the next apply. Records of other
+	// types with the same name aren't validation records and are left out.
+	actual, err := r.apiClient.getExistingRecords(ctx, current)
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var records []Record
+	for _, record := range current {
+		if result, ok := actual[newRecordKey(record)]; ok {
+			records = append(records, canonicalRecord(result))
+		}
+	}
+
+	state.Records = certificateValidationRecordSet(records)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *certificateValidationRecordsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state certificateValidationRecordsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	records, _, diags := plan.validationRecords(ctx)
+	resp.Diagnostics.Append(diags...)
+	current, diags := certificateValidationRecordsFromSet(ctx, state.Records)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	actual, err := r.apiClient.getExistingRecords(ctx, append(slices.Clone(records), current...))
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	changes, changed := certificateValidationChanges(records, current, actual)
+
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, changes))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Records = certificateValidationRecordSet(records)
+	plan.ValidationRecordFQDNs = certificateValidationFQDNs(records)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForRecords(ctx, plan.WaitForPropagation, changed)...)
+}
+
+func (r *certificateValidationRecordsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state certificateValidationRecordsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	records, diags := certificateValidationRecordsFromSet(ctx, state.Records)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	actual, err := r.apiClient.getExistingRecords(ctx, records)
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	changes, _ := certificateValidationChanges(nil, records, actual)
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, changes))...)
+}
+
+// certificateValidationChanges Returns the changes from the current to the desired records and the desired records
+// that change. Changes are based on the actual records, as a single record that was deleted or changed outside of
+// Terraform would fail the whole change set.
+func certificateValidationChanges(records []Record, current []Record, actual map[recordKey]Record) ([]RecordChange, []Record) {
+	existing := map[string]Record{}
+	for _, record := range current {
+		existing[record.Name] = record
+	}
+
+	var changes []RecordChange
+	var changed []Record
+	for _, record := range records {
+		old, ok := existing[record.Name]
+		delete(existing, record.Name)
+		now, exists := actual[newRecordKey(record)]
+		switch {
+		case ok && old.RRType != record.RRType:
+			// The type of a record can't be updated in place
+			if previous, exists := actual[newRecordKey(old)]; exists {
+				changes = append(changes, RecordChange{Action: ChangeActionDelete, Record: previous})
+			}
+			changes = append(changes, RecordChange{Action: ChangeActionCreate, Record: record})
+		case !ok || !exists:
+			changes = append(changes, RecordChange{Action: ChangeActionCreate, Record: record})
+		case canonicalRecord(now) != record:
+			changes = append(changes, RecordChange{Action: ChangeActionUpdate, Record: record})
+		default:
+			continue
+		}
+		changed = append(changed, record)
+	}
+
+	// Whatever is left is not needed for the validation anymore, unless it is gone already
+	for _, record := range current {
+		if _, ok := existing[record.Name]; !ok {
+			continue
+		}
+		if now, exists := actual[newRecordKey(record)]; exists {
+			changes = append(changes, RecordChange{Action: ChangeActionDelete, Record: now})
+		}
+	}
+	return changes, changed
+}
+
+// getExistingRecords Looks up which of the records exist by name and type, with one request per type
+func (c *ApiClient) getExistingRecords(ctx context.Context, records []Record) (map[recordKey]Record, diag.Diagnostics) {
+	wanted := map[recordKey]bool{}
+	var rrtypes []string
+	for _, record := range records {
+		key := newRecordKey(record)
+		wanted[key] = true
+		if !slices.Contains(rrtypes, key.rrtype) {
+			rrtypes = append(rrtypes, key.rrtype)
+		}
+	}
+
+	existing := map[recordKey]Record{}
+	for _, rrtype := range rrtypes {
+		results, err := c.getRecords(ctx, RecordFilter{RRType: rrtype})
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range results {
+			if key := newRecordKey(record); wanted[key] {
+				existing[key] = record
+			}
+		}
+	}
+	return existing, nil
+}
+
+// waitForRecords Waits for each of the records if the wait block is set
+func (r *certificateValidationRecordsResource) waitForRecords(ctx context.Context, blocks []waitModel, records []Record) fwdiag.Diagnostics {
+	config, diags := waitConfig(ctx, blocks)
+	if config == nil {
+		return diags
+	}
+	for _, record := range records {
+		diags.Append(frameworkDiagnostics(waitForRecord(ctx, config, record))...)
+		if diags.HasError() {
+			break
+		}
+	}
+	return diags
+}
+
+// validationRecords Converts the validation options into records, removing duplicates. The second result
+// is false as long as some of the options are unknown.
+func (m certificateValidationRecordsResourceModel) validationRecords(ctx context.Context) ([]Record, bool, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+	if m.DomainValidationOptions.IsUnknown() || m.TTL.IsUnknown() {
+		return nil, false, diags
+	}
+
+	var options []certificateValidationOptionModel
+	diags.Append(m.DomainValidationOptions.ElementsAs(ctx, &options, false)...)
+	if diags.HasError() {
+		return nil, false, diags
+	}
+
+	byName := map[string]Record{}
+	for _, option := range options {
+		if option.ResourceRecordName.IsUnknown() || option.ResourceRecordType.IsUnknown() || option.ResourceRecordValue.IsUnknown() {
+			return nil, false, diags
+		}
+		record := canonicalRecord(Record{
+			Name:   option.ResourceRecordName.ValueString(),
+			RRType: option.ResourceRecordType.ValueString(),
+			Value:  option.ResourceRecordValue.ValueString(),
+			TTL:    int(m.TTL.ValueInt64()),
+		})
+		if other, ok := byName[record.Name]; ok && other != record {
+			diags.AddAttributeError(path.Root("domain_validation_options"), "Conflicting validation records",
+				fmt.Sprintf("%s is required with the values %q and %q, it can only have one of them.", record.Name, other.Value, record.Value))
+			continue
+		}
+		byName[record.Name] = record
+	}
+
+	records := make([]Record, 0, len(byName))
+	for _, record := range byName {
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return records, true, diags
+}
+
+// canonicalRecord Normalizes a record, so records from the configuration and the API can be compared
+func canonicalRecord(record Record) Record {
+	rrtype := strings.ToUpper(record.RRType)
+	return Record{
+		Name:   canonicalName(record.Name),
+		RRType: rrtype,
+		Value:  canonicalValue(rrtype, record.Value),
+		TTL:    record.TTL,
+	}
+}
+
+// certificateValidationRecordSet Converts records into the value of the records attribute
+func certificateValidationRecordSet(records []Record) types.Set {
+	elements := make([]attr.Value, len(records))
+	for i, record := range records {
+		elements[i] = types.ObjectValueMust(certificateValidationRecordType.AttrTypes, map[string]attr.Value{
+			"name":   types.StringValue(record.Name),
+			"rrtype": types.StringValue(record.RRType),
+			"value":  types.StringValue(record.Value),
+			"ttl":    types.Int64Value(int64(record.TTL)),
+		})
+	}
+	return types.SetValueMust(certificateValidationRecordType, elements)
+}
+
+// certificateValidationRecordsFromSet Converts the value of the records attribute back into records
+func certificateValidationRecordsFromSet(ctx context.Context, set types.Set) ([]Record, fwdiag.Diagnostics) {
+	var models []certificateValidationRecordModel
+	diags := set.ElementsAs(ctx, &models, false)
+
+	records := make([]Record, len(models))
+	for i, model := range models {
+		records[i] = Record{
+			Name:   model.Name.ValueString(),
+			RRType: model.RRType.ValueString(),
+			Value:  model.Value.ValueString(),
+			TTL:    int(model.TTL.ValueInt64()),
+		}
+	}
+	return records, diags
+}
+
+// certificateValidationFQDNs Returns the names of the records in the order of the records
+func certificateValidationFQDNs(records []Record) types.List {
+	names := make([]attr.Value, len(records))
+	for i, record := range records {
+		names[i] = types.StringValue(record.Name)
+	}
+	return types.ListValueMust(types.StringType, names)
+}
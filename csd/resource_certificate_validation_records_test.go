@@ -0,0 +1,151 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCertificateValidationChanges(t *testing.T) {
+	record := func(name string, value string) Record {
+		return Record{Name: name, RRType: "CNAME", Value: value, TTL: 60}
+	}
+	app := record("_a.app.example.net", "_x.acm-validations.aws")
+	www := record("_b.www.example.net", "_y.acm-validations.aws")
+	api := record("_c.api.example.net", "_z.acm-validations.aws")
+
+	tests := []struct {
+		name        string
+		records     []Record
+		current     []Record
+		actual      []Record
+		want        []string
+		wantChanged []string
+	}{
+		{
+			name:    "unchanged",
+			records: []Record{app, www},
+			current: []Record{app, www},
+			actual:  []Record{app, www},
+		},
+		{
+			name:        "add and remove",
+			records:     []Record{app, api},
+			current:     []Record{app, www},
+			actual:      []Record{app, www},
+			want:        []string{"CREATE _c.api.example.net _z.acm-validations.aws", "DELETE _b.www.example.net _y.acm-validations.aws"},
+			wantChanged: []string{"_c.api.example.net"},
+		},
+		{
+			name:    "remove a record deleted outside of Terraform",
+			records: []Record{app},
+			current: []Record{app, www, api},
+			actual:  []Record{app, api},
+			want:    []string{"DELETE _c.api.example.net _z.acm-validations.aws"},
+		},
+		{
+			name:    "remove a record changed outside of Terraform",
+			current: []Record{www},
+			actual:  []Record{record(www.Name, "_other.acm-validations.aws")},
+			want:    []string{"DELETE _b.www.example.net _other.acm-validations.aws"},
+		},
+		{
+			name:        "recreate a record deleted outside of Terraform",
+			records:     []Record{app, www},
+			current:     []Record{app, www},
+			actual:      []Record{app},
+			want:        []string{"CREATE _b.www.example.net _y.acm-validations.aws"},
+			wantChanged: []string{"_b.www.example.net"},
+		},
+		{
+			name:        "update a value",
+			records:     []Record{record(app.Name, "_new.acm-validations.aws")},
+			current:     []Record{app},
+			actual:      []Record{app},
+			want:        []string{"UPDATE _a.app.example.net _new.acm-validations.aws"},
+			wantChanged: []string{"_a.app.example.net"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := map[recordKey]Record{}
+			for _, record := range tt.actual {
+				actual[newRecordKey(record)] = record
+			}
+
+			changes, changed := certificateValidationChanges(tt.records, tt.current, actual)
+			var got, gotChanged []string
+			for _, change := range changes {
+				got = append(got, fmt.Sprintf("%s %s %s", change.Action, change.Record.Name, change.Record.Value))
+			}
+			for _, record := range changed {
+				gotChanged = append(gotChanged, record.Name)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("certificateValidationChanges() changes = %q, want %q", got, tt.want)
+			}
+			if fmt.Sprint(gotChanged) != fmt.Sprint(tt.wantChanged) {
+				t.Errorf("certificateValidationChanges() changed = %q, want %q", gotChanged, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestCertificateValidationRecordsDelete(t *testing.T) {
+	app := Record{Name: "_a.app.example.net", RRType: "CNAME", Value: "_x.acm-validations.aws", TTL: 60}
+	www := Record{Name: "_b.www.example.net", RRType: "CNAME", Value: "_y.acm-validations.aws", TTL: 60}
+	other := Record{Name: "_a.app.example.net", RRType: "TXT", Value: "unrelated", TTL: 300}
+
+	// The record of www was deleted outside of Terraform
+	api := &recordsAPI{records: []Record{app, other}}
+	r := NewCertificateValidationRecordsResource().(*certificateValidationRecordsResource)
+	r.apiClient = newTestAPI(t, api)
+	state := testResourceState(t, r, map[string]attr.Value{
+		"id":      types.StringValue(app.Name),
+		"records": certificateValidationRecordSet([]Record{app, www}),
+	})
+
+	var resp resource.DeleteResponse
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete() = %v", resp.Diagnostics)
+	}
+	if len(api.records) != 1 || api.records[0] != other {
+		t.Errorf("records = %v, want only the unrelated record left", api.records)
+	}
+}
+
+func TestCertificateValidationRecordsRead(t *testing.T) {
+	app := Record{Name: "_a.app.example.net", RRType: "CNAME", Value: "_x.acm-validations.aws", TTL: 60}
+	www := Record{Name: "_b.www.example.net", RRType: "CNAME", Value: "_y.acm-validations.aws", TTL: 60}
+	other := Record{Name: "_a.app.example.net", RRType: "TXT", Value: "unrelated", TTL: 300}
+
+	// The TXT record comes first for lookups by name, the record of www was deleted outside of Terraform
+	api := &recordsAPI{records: []Record{other, app}}
+	r := NewCertificateValidationRecordsResource().(*certificateValidationRecordsResource)
+	r.apiClient = newTestAPI(t, api)
+	state := testResourceState(t, r, map[string]attr.Value{
+		"id":      types.StringValue(app.Name),
+		"records": certificateValidationRecordSet([]Record{app, www}),
+	})
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", resp.Diagnostics)
+	}
+
+	var got certificateValidationRecordsResourceModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+	records, diags := certificateValidationRecordsFromSet(context.Background(), got.Records)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(records) != 1 || records[0] != canonicalRecord(app) {
+		t.Errorf("records = %v, want only the validation record of app", records)
+	}
+}
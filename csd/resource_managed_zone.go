@@ -0,0 +1,342 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource               = &managedZoneResource{}
+	_ resource.ResourceWithConfigure  = &managedZoneResource{}
+	_ resource.ResourceWithModifyPlan = &managedZoneResource{}
+)
+
+// emptyHostedZoneRecords Are the SOA and NS records every hosted zone has, Route53 refuses deleting zones with more
+const emptyHostedZoneRecords = 2
+
+type managedZoneResource struct {
+	apiClient *ApiClient
+}
+
+type managedZoneResourceModel struct {
+	ID                 types.String   `tfsdk:"id"`
+	ZoneID             types.String   `tfsdk:"zone_id"`
+	Name               types.String   `tfsdk:"name"`
+	Comment            types.String   `tfsdk:"comment"`
+	NameServers        types.List     `tfsdk:"name_servers"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	WaitForDelegation  []waitModel    `tfsdk:"wait_for_delegation"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+func NewManagedZoneResource() resource.Resource {
+	return &managedZoneResource{}
+}
+
+func (r *managedZoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_managed_zone"
+}
+
+func (r *managedZoneResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates a Route53 hosted zone in the AWS account of the provider and delegates it to its name servers, " +
+			"replacing the pair of `aws_route53_zone` and `csd_zone_delegation`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ID of the hosted zone",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"zone_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the hosted zone, e.g. for `aws_route53_record`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "FQDN of the DNS zone",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				MarkdownDescription: "Comment of the hosted zone",
+				Optional:            true,
+			},
+			"name_servers": schema.ListAttribute{
+				MarkdownDescription: "Name servers Route53 assigned to the hosted zone, the zone is delegated to them. " +
+					"If the delegation went missing or points elsewhere, the next apply restores it.",
+				ElementType: types.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Prevents destroying the zone, it has to be set to `false` in a prior apply to allow deletion",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for_delegation": waitBlock("Wait until the parent zone name servers delegate to `name_servers` after creation"),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *managedZoneResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *managedZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan managedZoneResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	hostedZone, err := createHostedZone(ctx, r.apiClient, canonicalName(plan.Name.ValueString()), plan.Comment.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create hosted zone", err.Error())
+		return
+	}
+
+	zoneDelegation, diagsDelegation := r.apiClient.createZoneDelegation(ctx, ZoneDelegation{
+		Name:        hostedZone.Name,
+		NameServers: hostedZone.NameServers,
+		DSRecords:   []DSRecord{},
+	})
+	if diagsDelegation.HasError() {
+		resp.Diagnostics.Append(frameworkDiagnostics(diagsDelegation)...)
+		// Don't leave an unreachable zone behind
+		if err := deleteHostedZone(ctx, r.apiClient, hostedZone.Id); err != nil {
+			resp.Diagnostics.AddWarning(fmt.Sprintf("Couldn't remove hosted zone %s again", hostedZone.Id),
+				fmt.Sprintf("Please delete it manually: %s", err))
+		}
+		return
+	}
+
+	plan.ID = types.StringValue(hostedZone.Id)
+	plan.ZoneID = types.StringValue(hostedZone.Id)
+	plan.setHostedZone(hostedZone)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, diags := waitConfig(ctx, plan.WaitForDelegation)
+	resp.Diagnostics.Append(diags...)
+	if config != nil {
+		resp.Diagnostics.Append(createdWaitDiagnostics(frameworkDiagnostics(waitForZoneDelegation(ctx, config, zoneDelegation)))...)
+	}
+}
+
+func (r *managedZoneResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state managedZoneResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	hostedZone, err := getHostedZone(ctx, r.apiClient, state.ID.ValueString())
+	if isNoSuchHostedZone(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Couldn't read hosted zone", err.Error())
+		return
+	}
+	state.setHostedZone(hostedZone)
+
+	// A delegation to other name servers is as broken as none, both are planned to be restored
+	zoneDelegation, diagsDelegation := r.apiClient.getZoneDelegation(ctx, hostedZone.Name)
+	if isNotFound(diagsDelegation) {
+		resp.Diagnostics.AddWarning(fmt.Sprintf("Zone delegation of %s is missing", hostedZone.Name),
+			"The next apply delegates the zone to the name servers of the hosted zone again.")
+		state.NameServers = types.ListNull(types.StringType)
+	} else if diagsDelegation.HasError() {
+		resp.Diagnostics.Append(frameworkDiagnostics(diagsDelegation)...)
+		return
+	} else if !equalNameServers(zoneDelegation.NameServers, hostedZone.NameServers) {
+		resp.Diagnostics.AddWarning(fmt.Sprintf("Zone delegation of %s points to other name servers", hostedZone.Name),
+			fmt.Sprintf("It delegates to %s, the next apply delegates the zone to the name servers of the hosted zone again.",
+				strings.Join(zoneDelegation.NameServers, ", ")))
+		state.NameServers = types.ListNull(types.StringType)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ModifyPlan Plans an update if Read found the delegation missing, the name servers are only known after restoring it
+func (r *managedZoneResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state managedZoneResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.NameServers.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name_servers"), types.ListUnknown(types.StringType))...)
+	}
+}
+
+func (r *managedZoneResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state managedZoneResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Everything else is either replaced or only stored in state
+	if !plan.Comment.Equal(state.Comment) {
+		if err := updateHostedZoneComment(ctx, r.apiClient, state.ID.ValueString(), plan.Comment.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Couldn't update hosted zone", err.Error())
+			return
+		}
+	}
+
+	if state.NameServers.IsNull() {
+		hostedZone, diags := r.restoreZoneDelegation(ctx, state.ID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.NameServers = stringList(hostedZone.NameServers)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// restoreZoneDelegation Delegates the zone to the name servers of the hosted zone again, keeping the DS records if the
+// delegation still exists
+func (r *managedZoneResource) restoreZoneDelegation(ctx context.Context, id string) (HostedZone, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+
+	hostedZone, err := getHostedZone(ctx, r.apiClient, id)
+	if err != nil {
+		diags.AddError("Couldn't read hosted zone", err.Error())
+		return hostedZone, diags
+	}
+
+	zoneDelegation := ZoneDelegation{Name: hostedZone.Name, NameServers: hostedZone.NameServers}
+	_, diagsDelegation := r.apiClient.getZoneDelegation(ctx, hostedZone.Name)
+	if isNotFound(diagsDelegation) {
+		zoneDelegation.DSRecords = []DSRecord{}
+		_, diagsDelegation = r.apiClient.createZoneDelegation(ctx, zoneDelegation)
+	} else if !diagsDelegation.HasError() {
+		_, diagsDelegation = r.apiClient.updateZoneDelegation(ctx, zoneDelegation)
+	}
+	return hostedZone, append(diags, frameworkDiagnostics(diagsDelegation)...)
+}
+
+// Delete Removes the delegation before the hosted zone, so the parent zone never delegates to a deleted zone. To not
+// remove the delegation of a zone that can't be deleted, it checks first that the zone holds no other records.
+func (r *managedZoneResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state managedZoneResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name := canonicalName(state.Name.ValueString())
+
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Managed zone is protected against deletion",
+			fmt.Sprintf("Set deletion_protection = false for %s and apply that change before destroying it. "+
+				"Keep in mind that resolvers may cache the NS records for up to 2 days.", name))
+		return
+	}
+
+	hostedZone, err := getHostedZone(ctx, r.apiClient, state.ID.ValueString())
+	if err != nil && !isNoSuchHostedZone(err) {
+		resp.Diagnostics.AddError("Couldn't read hosted zone", err.Error())
+		return
+	} else if hostedZone.RecordCount > emptyHostedZoneRecords {
+		resp.Diagnostics.AddError("Hosted zone isn't empty",
+			fmt.Sprintf("The hosted zone %s still holds %d records besides SOA and NS, please delete them first. "+
+				"The zone delegation of %s is left in place.", state.ID.ValueString(), hostedZone.RecordCount-emptyHostedZoneRecords, name))
+		return
+	}
+
+	if err := r.apiClient.deleteZoneDelegation(ctx, name); err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+		return
+	}
+
+	if err := deleteHostedZone(ctx, r.apiClient, state.ID.ValueString()); err != nil && !isNoSuchHostedZone(err) {
+		resp.Diagnostics.AddError("Couldn't delete hosted zone",
+			fmt.Sprintf("The zone delegation of %s is removed already, but the hosted zone %s is left: %s", name, state.ID.ValueString(), err))
+	}
+}
+
+// setHostedZone Sets the Terraform values from a hosted zone, keeping equivalent values as configured
+func (m *managedZoneResourceModel) setHostedZone(hostedZone HostedZone) {
+	m.Name = keepEquivalent(m.Name, hostedZone.Name, func(a, b string) bool {
+		return canonicalName(a) == canonicalName(b)
+	})
+	// Route53 doesn't distinguish between an empty and no comment
+	if hostedZone.Comment != "" || !m.Comment.IsNull() {
+		m.Comment = types.StringValue(hostedZone.Comment)
+	}
+	m.NameServers = stringList(hostedZone.NameServers)
+}
@@ -0,0 +1,333 @@
+package csd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const route53Namespace = "https://route53.amazonaws.com/doc/2013-04-01/"
+
+// managedZoneAPI Stands in for Route53 and the zone delegations of the API, holding at most one zone
+type managedZoneAPI struct {
+	mutex      sync.Mutex
+	hostedZone *HostedZone
+	delegation *ZoneDelegation
+	// rejectDelegation Makes creating zone delegations fail like for a name outside of the allowed zones
+	rejectDelegation bool
+	requests         []string
+}
+
+func (a *managedZoneAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.requests = append(a.requests, r.Method+" "+r.URL.Path)
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/2013-04-01/hostedzone":
+		a.hostedZone = &HostedZone{Id: "Z1", Name: "app.example.net", NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.net"}, RecordCount: 2}
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `<CreateHostedZoneResponse xmlns="%s">%s<ChangeInfo><Id>C1</Id><Status>PENDING</Status>`+
+			`<SubmittedAt>2024-01-01T00:00:00Z</SubmittedAt></ChangeInfo></CreateHostedZoneResponse>`, route53Namespace, a.hostedZoneXML())
+	case strings.HasPrefix(r.URL.Path, "/2013-04-01/hostedzone/"):
+		w.Header().Set("Content-Type", "text/xml")
+		if a.hostedZone == nil {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>NoSuchHostedZone</Code><Message>No hosted zone found</Message></Error></ErrorResponse>`)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `<GetHostedZoneResponse xmlns="%s">%s</GetHostedZoneResponse>`, route53Namespace, a.hostedZoneXML())
+		case http.MethodDelete:
+			if a.hostedZone.RecordCount > emptyHostedZoneRecords {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>HostedZoneNotEmpty</Code><Message>The hosted zone contains resource records</Message></Error></ErrorResponse>`)
+				return
+			}
+			a.hostedZone = nil
+			fmt.Fprintf(w, `<DeleteHostedZoneResponse xmlns="%s"><ChangeInfo><Id>C2</Id><Status>PENDING</Status>`+
+				`<SubmittedAt>2024-01-01T00:00:00Z</SubmittedAt></ChangeInfo></DeleteHostedZoneResponse>`, route53Namespace)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/zone_delegations":
+		if a.rejectDelegation {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message":"app.example.net is not part of an allowed zone"}`))
+			return
+		}
+		var zoneDelegation ZoneDelegation
+		json.NewDecoder(r.Body).Decode(&zoneDelegation)
+		a.delegation = &zoneDelegation
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(zoneDelegation)
+	case strings.HasPrefix(r.URL.Path, "/v2/zone_delegations/"):
+		if a.delegation == nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(a.delegation)
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(a.delegation)
+			json.NewEncoder(w).Encode(a.delegation)
+		case http.MethodDelete:
+			a.delegation = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *managedZoneAPI) hostedZoneXML() string {
+	var nameServers string
+	for _, nameServer := range a.hostedZone.NameServers {
+		nameServers += "<NameServer>" + nameServer + "</NameServer>"
+	}
+	return fmt.Sprintf(`<HostedZone><Id>/hostedzone/%s</Id><Name>%s.</Name><CallerReference>test</CallerReference>`+
+		`<ResourceRecordSetCount>%d</ResourceRecordSetCount></HostedZone><DelegationSet><NameServers>%s</NameServers></DelegationSet>`,
+		a.hostedZone.Id, a.hostedZone.Name, a.hostedZone.RecordCount, nameServers)
+}
+
+// newManagedZoneResource Returns the resource with its schema and a state value of it, nulls unless given in values
+func newManagedZoneResource(t *testing.T, api *managedZoneAPI) (*managedZoneResource, func(values map[string]tftypes.Value) tftypes.Value) {
+	ctx := context.Background()
+	r := NewManagedZoneResource().(*managedZoneResource)
+	r.apiClient = newTestAPI(t, api)
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	return r, func(values map[string]tftypes.Value) tftypes.Value {
+		all := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			all[name] = tftypes.NewValue(attributeType, nil)
+		}
+		all["wait_for_delegation"] = tftypes.NewValue(objectType.AttributeTypes["wait_for_delegation"], []tftypes.Value{})
+		for name, value := range values {
+			all[name] = value
+		}
+		return tftypes.NewValue(objectType, all)
+	}
+}
+
+func managedZoneState(r *managedZoneResource, raw tftypes.Value) tfsdk.State {
+	var schemaResp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	return tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+}
+
+func TestManagedZoneCreateRollback(t *testing.T) {
+	api := &managedZoneAPI{rejectDelegation: true}
+	r, value := newManagedZoneResource(t, api)
+	plan := value(map[string]tftypes.Value{
+		"name":                tftypes.NewValue(tftypes.String, "app.example.net"),
+		"deletion_protection": tftypes.NewValue(tftypes.Bool, true),
+		"id":                  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"zone_id":             tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"name_servers":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+	})
+
+	state := managedZoneState(r, tftypes.NewValue(plan.Type(), nil))
+	req := resource.CreateRequest{Plan: tfsdk.Plan{Schema: state.Schema, Raw: plan}}
+	resp := resource.CreateResponse{State: state}
+	r.Create(context.Background(), req, &resp)
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Couldn't create zone delegation" {
+		t.Errorf("Create() = %v, want the delegation error", resp.Diagnostics)
+	}
+	if api.hostedZone != nil {
+		t.Errorf("hosted zone %s is left behind", api.hostedZone.Id)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("Create() stored state %v", resp.State.Raw)
+	}
+	want := []string{"POST /2013-04-01/hostedzone", "POST /v2/zone_delegations", "DELETE /2013-04-01/hostedzone/Z1"}
+	if strings.Join(api.requests, ", ") != strings.Join(want, ", ") {
+		t.Errorf("requests = %v, want %v", api.requests, want)
+	}
+}
+
+func TestManagedZoneDelete(t *testing.T) {
+	tests := []struct {
+		name               string
+		deletionProtection bool
+		recordCount        int64
+		hostedZoneGone     bool
+		wantErr            string
+		wantRequests       []string
+	}{
+		{
+			name:        "empty zone",
+			recordCount: 2,
+			wantRequests: []string{
+				"GET /2013-04-01/hostedzone/Z1", "DELETE /v2/zone_delegations/app.example.net", "DELETE /2013-04-01/hostedzone/Z1",
+			},
+		},
+		{
+			name:               "protected zone",
+			deletionProtection: true,
+			recordCount:        2,
+			wantErr:            "Managed zone is protected against deletion",
+		},
+		{
+			name:         "zone with records",
+			recordCount:  5,
+			wantErr:      "Hosted zone isn't empty",
+			wantRequests: []string{"GET /2013-04-01/hostedzone/Z1"},
+		},
+		{
+			name:           "hosted zone deleted already",
+			hostedZoneGone: true,
+			wantRequests: []string{
+				"GET /2013-04-01/hostedzone/Z1", "DELETE /v2/zone_delegations/app.example.net", "DELETE /2013-04-01/hostedzone/Z1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nameServers := []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.net"}
+			api := &managedZoneAPI{
+				hostedZone: &HostedZone{Id: "Z1", Name: "app.example.net", NameServers: nameServers, RecordCount: tt.recordCount},
+				delegation: &ZoneDelegation{Name: "app.example.net", NameServers: nameServers},
+			}
+			if tt.hostedZoneGone {
+				api.hostedZone = nil
+			}
+			r, value := newManagedZoneResource(t, api)
+			state := managedZoneState(r, value(map[string]tftypes.Value{
+				"id":                  tftypes.NewValue(tftypes.String, "Z1"),
+				"zone_id":             tftypes.NewValue(tftypes.String, "Z1"),
+				"name":                tftypes.NewValue(tftypes.String, "app.example.net"),
+				"deletion_protection": tftypes.NewValue(tftypes.Bool, tt.deletionProtection),
+			}))
+
+			var resp resource.DeleteResponse
+			r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+			if tt.wantErr == "" && resp.Diagnostics.HasError() {
+				t.Fatalf("Delete() = %v", resp.Diagnostics)
+			} else if tt.wantErr != "" && (!resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != tt.wantErr) {
+				t.Errorf("Delete() = %v, want %q", resp.Diagnostics, tt.wantErr)
+			}
+			if tt.wantErr != "" && api.delegation == nil {
+				t.Error("zone delegation was deleted although the zone is kept")
+			}
+			if strings.Join(api.requests, ", ") != strings.Join(tt.wantRequests, ", ") {
+				t.Errorf("requests = %v, want %v", api.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestManagedZoneRead(t *testing.T) {
+	nameServers := []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.net"}
+	tests := []struct {
+		name            string
+		delegation      *ZoneDelegation
+		wantRemoved     bool
+		wantNameServers bool
+		wantWarning     bool
+	}{
+		{
+			name:            "delegated",
+			delegation:      &ZoneDelegation{Name: "app.example.net", NameServers: []string{"NS-2.awsdns-02.net.", "ns-1.awsdns-01.org"}},
+			wantNameServers: true,
+		},
+		{
+			name:        "hosted zone deleted",
+			delegation:  &ZoneDelegation{Name: "app.example.net", NameServers: nameServers},
+			wantRemoved: true,
+		},
+		{
+			name:        "delegation missing",
+			wantWarning: true,
+		},
+		{
+			name:        "delegation to other name servers",
+			delegation:  &ZoneDelegation{Name: "app.example.net", NameServers: []string{"ns-3.awsdns-03.com", "ns-4.awsdns-04.co.uk"}},
+			wantWarning: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &managedZoneAPI{
+				hostedZone: &HostedZone{Id: "Z1", Name: "app.example.net", NameServers: nameServers, RecordCount: 2},
+				delegation: tt.delegation,
+			}
+			if tt.wantRemoved {
+				api.hostedZone = nil
+			}
+			r, value := newManagedZoneResource(t, api)
+			state := managedZoneState(r, value(map[string]tftypes.Value{
+				"id":                  tftypes.NewValue(tftypes.String, "Z1"),
+				"zone_id":             tftypes.NewValue(tftypes.String, "Z1"),
+				"name":                tftypes.NewValue(tftypes.String, "app.example.net"),
+				"deletion_protection": tftypes.NewValue(tftypes.Bool, true),
+			}))
+
+			resp := resource.ReadResponse{State: state}
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read() = %v", resp.Diagnostics)
+			}
+			if resp.State.Raw.IsNull() != tt.wantRemoved {
+				t.Fatalf("Read() removed = %v, want %v", resp.State.Raw.IsNull(), tt.wantRemoved)
+			}
+			if tt.wantRemoved {
+				return
+			}
+
+			var got managedZoneResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &got)...)
+			if got.NameServers.IsNull() == tt.wantNameServers {
+				t.Errorf("name_servers = %v, want set %v", got.NameServers, tt.wantNameServers)
+			}
+			if (resp.Diagnostics.WarningsCount() > 0) != tt.wantWarning {
+				t.Errorf("Read() = %v, want warning %v", resp.Diagnostics, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestManagedZoneUpdateRestoresDelegation(t *testing.T) {
+	api := &managedZoneAPI{
+		hostedZone: &HostedZone{Id: "Z1", Name: "app.example.net", NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.net"}, RecordCount: 2},
+	}
+	r, value := newManagedZoneResource(t, api)
+	values := map[string]tftypes.Value{
+		"id":                  tftypes.NewValue(tftypes.String, "Z1"),
+		"zone_id":             tftypes.NewValue(tftypes.String, "Z1"),
+		"name":                tftypes.NewValue(tftypes.String, "app.example.net"),
+		"deletion_protection": tftypes.NewValue(tftypes.Bool, true),
+	}
+	state := managedZoneState(r, value(values))
+	values["name_servers"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue)
+	plan := tfsdk.Plan{Schema: state.Schema, Raw: value(values)}
+
+	resp := resource.UpdateResponse{State: state}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() = %v", resp.Diagnostics)
+	}
+
+	if api.delegation == nil || !equalNameServers(api.delegation.NameServers, api.hostedZone.NameServers) {
+		t.Errorf("zone delegation = %+v, want the name servers of the hosted zone", api.delegation)
+	}
+	var got managedZoneResourceModel
+	resp.State.Get(context.Background(), &got)
+	if len(got.NameServers.Elements()) != 2 {
+		t.Errorf("name_servers = %v, want the name servers of the hosted zone", got.NameServers)
+	}
+}
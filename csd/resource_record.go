@@ -2,162 +2,339 @@ package csd
 
 import (
 	"context"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"fmt"
 	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+var (
+	_ resource.Resource                   = &recordResource{}
+	_ resource.ResourceWithConfigure      = &recordResource{}
+	_ resource.ResourceWithImportState    = &recordResource{}
+	_ resource.ResourceWithIdentity       = &recordResource{}
+	_ resource.ResourceWithValidateConfig = &recordResource{}
 )
 
-func resourceRecord() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceRecordCreate,
-		ReadContext:   resourceRecordRead,
-		UpdateContext: resourceRecordUpdate,
-		DeleteContext: resourceRecordDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Description: "Name of the DNS record as FQDN",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+type recordResource struct {
+	apiClient *ApiClient
+}
+
+type recordResourceModel struct {
+	ID                 types.String   `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	Value              types.String   `tfsdk:"value"`
+	TTL                types.Int64    `tfsdk:"ttl"`
+	RRType             types.String   `tfsdk:"rrtype"`
+	WaitForPropagation []waitModel    `tfsdk:"wait_for_propagation"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// recordIdentityModel Identifies a record in import blocks, names are unique per record type
+type recordIdentityModel struct {
+	Name   types.String `tfsdk:"name"`
+	RRType types.String `tfsdk:"rrtype"`
+}
+
+func NewRecordResource() resource.Resource {
+	return &recordResource{}
+}
+
+func (r *recordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_record"
+}
+
+func (r *recordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Normalized name of the record",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"value": {
-				Description: "Value of the DNS record (FQDN of Akamai Edgekey Hostname in case of CNAME)",
-				Type:        schema.TypeString,
-				Required:    true,
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the DNS record as FQDN",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"ttl": {
-				Description: "Time to life for the record in seconds",
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     3600,
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Value of the DNS record (FQDN of Akamai Edgekey Hostname in case of CNAME)",
+				Required:            true,
 			},
-			"rrtype": {
-				Description: "The type of DNS record",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "Time to life for the record in seconds",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3600),
+			},
+			"rrtype": schema.StringAttribute{
+				MarkdownDescription: "The type of DNS record",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 		},
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+		Blocks: map[string]schema.Block{
+			"wait_for_propagation": waitBlock("Wait until the authoritative name servers serve the new value after changes, " +
+				"supported for the record types A, AAAA, CNAME, NS, SOA and TXT"),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
-func resourceRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*ApiClient)
-
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+func (r *recordResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				Description:       "Name of the DNS record as FQDN",
+				RequiredForImport: true,
+			},
+			"rrtype": identityschema.StringAttribute{
+				Description:       "The type of DNS record",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
 
-	record := Record{
-		Name:   strings.ToLower(d.Get("name").(string)),
-		RRType: strings.ToUpper(d.Get("rrtype").(string)),
-		Value:  d.Get("value").(string),
-		TTL:    d.Get("ttl").(int),
+// ValidateConfig Rejects waiting for record types the DNS client can't query, it would only run into the timeout
+func (r *recordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rrtype types.String
+	var wait types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rrtype"), &rrtype)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("wait_for_propagation"), &wait)...)
+	if resp.Diagnostics.HasError() || rrtype.IsNull() || rrtype.IsUnknown() || wait.IsNull() || len(wait.Elements()) == 0 {
+		return
 	}
 
-	result, err := apiClient.createRecord(record)
-	if err != nil {
-		return err
+	if !isQueryableType(rrtype.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_propagation"), "Unsupported record type for waiting",
+			fmt.Sprintf("Waiting for propagation supports the record types %s, but not %s.",
+				strings.Join(queryableTypes(), ", "), rrtype.ValueString()))
 	}
+}
 
-	d.SetId(strings.ToLower(result.Name))
-	if err := d.Set("name", result.Name); err != nil {
-		return diag.FromErr(err)
+func (r *recordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *recordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan recordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err := d.Set("rrtype", result.RRType); err != nil {
-		return diag.FromErr(err)
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err := d.Set("value", result.Value); err != nil {
-		return diag.FromErr(err)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	record := plan.record()
+	result, err := r.apiClient.createRecord(ctx, record)
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err := d.Set("ttl", result.TTL); err != nil {
-		return diag.FromErr(err)
+
+	plan.ID = types.StringValue(canonicalName(result.Name))
+	plan.setRecord(result)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newRecordIdentity(result))...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	return diags
+	config, diags := waitConfig(ctx, plan.WaitForPropagation)
+	resp.Diagnostics.Append(diags...)
+	if config != nil {
+		resp.Diagnostics.Append(frameworkDiagnostics(waitForRecord(ctx, config, result))...)
+	}
 }
 
-func resourceRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*ApiClient)
+func (r *recordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state recordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	timeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The name may hold records of other types as well, which are none of this resource's business
+	record, err := r.apiClient.findRecord(ctx, state.ID.ValueString(), state.RRType.ValueString())
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	id := d.Id()
+	state.setRecord(record)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newRecordIdentity(record))...)
+}
 
-	record, err := apiClient.getRecord(id)
-	if err != nil {
-		return err
+func (r *recordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state recordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// sets the response body (record object) to Terraform record data source
-	if err := d.Set("name", record.Name); err != nil {
-		return diag.FromErr(err)
+	timeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err := d.Set("rrtype", record.RRType); err != nil {
-		return diag.FromErr(err)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Check for resource changes (only value and ttl are relevant at the moment)
+	if plan.Value.Equal(state.Value) && plan.TTL.Equal(state.TTL) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
 	}
-	if err := d.Set("value", record.Value); err != nil {
-		return diag.FromErr(err)
+
+	// A change set addresses the record by name and type, unlike updates by name only
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, []RecordChange{{Action: ChangeActionUpdate, Record: plan.record()}}))...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err := d.Set("ttl", record.TTL); err != nil {
-		return diag.FromErr(err)
+	result, err := r.apiClient.findRecord(ctx, plan.ID.ValueString(), plan.RRType.ValueString())
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	return diags
-}
+	plan.setRecord(result)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newRecordIdentity(result))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-func resourceRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Check for resource changes (only value and ttl are relevant at the moment)
-	if d.HasChange("value") || d.HasChange("ttl") {
-		apiClient := m.(*ApiClient)
-
-		record := Record{
-			Name:   strings.ToLower(d.Get("name").(string)),
-			RRType: strings.ToUpper(d.Get("rrtype").(string)),
-			Value:  d.Get("value").(string),
-			TTL:    d.Get("ttl").(int),
-		}
+	config, diags := waitConfig(ctx, plan.WaitForPropagation)
+	resp.Diagnostics.Append(diags...)
+	if config != nil {
+		resp.Diagnostics.Append(frameworkDiagnostics(waitForRecord(ctx, config, result))...)
+	}
+}
 
-		result, err := apiClient.updateRecord(record)
-		if err != nil {
-			return err
-		}
+func (r *recordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state recordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		if err := d.Set("name", result.Name); err != nil {
-			return diag.FromErr(err)
-		}
-		if err := d.Set("rrtype", result.RRType); err != nil {
-			return diag.FromErr(err)
-		}
-		if err := d.Set("value", result.Value); err != nil {
-			return diag.FromErr(err)
-		}
-		if err := d.Set("ttl", result.TTL); err != nil {
-			return diag.FromErr(err)
-		}
+	timeout, diags := state.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	return resourceRecordRead(ctx, d, m)
+	// Only the record of the managed type is deleted, other records with the same name stay
+	record, err := r.apiClient.findRecord(ctx, state.ID.ValueString(), state.RRType.ValueString())
+	if isNotFound(err) {
+		return
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, []RecordChange{{Action: ChangeActionDelete, Record: record}}))...)
 }
 
-func resourceRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*ApiClient)
+// ImportState Imports a record by name or by identity, the record has to exist already
+func (r *recordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	var record Record
+	var err diag.Diagnostics
+	if req.ID != "" {
+		record, err = r.apiClient.getRecord(ctx, canonicalName(req.ID))
+	} else {
+		var identity recordIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		record, err = r.apiClient.findRecord(ctx, identity.Name.ValueString(), identity.RRType.ValueString())
+	}
+	if isNotFound(err) {
+		resp.Diagnostics.AddError("Cannot import non-existent record", fmt.Sprintf("%s, please check the name and type.", err[0].Detail))
+		return
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	id := d.Id()
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), canonicalName(record.Name))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), record.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("rrtype"), record.RRType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value"), record.Value)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ttl"), record.TTL)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newRecordIdentity(record))...)
+}
 
-	if err := apiClient.deleteRecord(id); err != nil {
-		return err
+// newRecordIdentity Returns the identity of a record from the API
+func newRecordIdentity(record Record) recordIdentityModel {
+	return recordIdentityModel{
+		Name:   types.StringValue(canonicalName(record.Name)),
+		RRType: types.StringValue(strings.ToUpper(record.RRType)),
 	}
+}
 
-	// d.SetId("") is automatically called assuming delete returns no errors, but
-	// it is added here for explicitness.
-	d.SetId("")
+// record Converts the Terraform values into an API object
+func (m recordResourceModel) record() Record {
+	return Record{
+		Name:   canonicalName(m.Name.ValueString()),
+		RRType: strings.ToUpper(m.RRType.ValueString()),
+		Value:  m.Value.ValueString(),
+		TTL:    int(m.TTL.ValueInt64()),
+	}
+}
 
-	return diags
+// setRecord Sets the Terraform values from an API object, keeping equivalent values as configured
+func (m *recordResourceModel) setRecord(record Record) {
+	m.Name = keepEquivalent(m.Name, record.Name, func(a, b string) bool {
+		return canonicalName(a) == canonicalName(b)
+	})
+	m.RRType = keepEquivalent(m.RRType, record.RRType, strings.EqualFold)
+	m.Value = keepEquivalent(m.Value, record.Value, func(a, b string) bool {
+		return canonicalValue(record.RRType, a) == canonicalValue(record.RRType, b)
+	})
+	m.TTL = types.Int64Value(int64(record.TTL))
 }
@@ -0,0 +1,401 @@
+package csd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// recordSetApex Is the key of the record named like name_suffix itself
+const recordSetApex = "@"
+
+var (
+	_ resource.Resource              = &recordSetResource{}
+	_ resource.ResourceWithConfigure = &recordSetResource{}
+)
+
+type recordSetResource struct {
+	apiClient *ApiClient
+}
+
+type recordSetResourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	NameSuffix types.String   `tfsdk:"name_suffix"`
+	TTL        types.Int64    `tfsdk:"ttl"`
+	Records    types.Map      `tfsdk:"records"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// recordSetRecordModel Maps an element of the records attribute
+type recordSetRecordModel struct {
+	RRType types.String `tfsdk:"rrtype"`
+	Value  types.String `tfsdk:"value"`
+}
+
+var recordSetRecordType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"rrtype": types.StringType,
+		"value":  types.StringType,
+	},
+}
+
+func NewRecordSetResource() resource.Resource {
+	return &recordSetResource{}
+}
+
+func (r *recordSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_record_set"
+}
+
+func (r *recordSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages many DNS records below one name as a unit, with a single API call to refresh all of them " +
+			"and one change set to apply all changes together. Records below the name that are not part of `records` are left alone.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Normalized `name_suffix`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name_suffix": schema.StringAttribute{
+				MarkdownDescription: "Name the records are placed below, e.g. the name of a zone",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				MarkdownDescription: "Time to life for all records in seconds",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3600),
+			},
+			"records": schema.MapAttribute{
+				MarkdownDescription: "Records by name relative to `name_suffix`, `@` stands for `name_suffix` itself. " +
+					"Objects with `rrtype` and `value` like in `csd_record`.",
+				ElementType: recordSetRecordType,
+				Required:    true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(recordSetKeyRegex,
+						"must be `@` or a relative name without leading or trailing dot")),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *recordSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *recordSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan recordSetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	plan.ID = types.StringValue(canonicalName(plan.NameSuffix.ValueString()))
+	if !r.apply(ctx, &plan, recordSetResourceModel{Records: types.MapNull(recordSetRecordType)}, &resp.Diagnostics) {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *recordSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state recordSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	known, diags := state.records(ctx)
+	resp.Diagnostics.Append(diags...)
+	actual, err := r.apiClient.getRecordSet(ctx, state.NameSuffix.ValueString())
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.setRecords(known, actual)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *recordSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state recordSetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if !r.apply(ctx, &plan, state, &resp.Diagnostics) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *recordSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state recordSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	managed, diags := state.records(ctx)
+	resp.Diagnostics.Append(diags...)
+	actual, err := r.apiClient.getRecordSet(ctx, state.NameSuffix.ValueString())
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var changes []RecordChange
+	for _, record := range managed {
+		if current, ok := actual[newRecordKey(record)]; ok {
+			changes = append(changes, RecordChange{Action: ChangeActionDelete, Record: current})
+		}
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, changes))...)
+}
+
+// apply Brings the records below name_suffix in line with the plan with one change set and sets the records
+// that exist afterwards, so the state is right even if the change set failed. It returns false if the plan was
+// rejected before anything was changed.
+func (r *recordSetResource) apply(ctx context.Context, plan *recordSetResourceModel, state recordSetResourceModel, diags *fwdiag.Diagnostics) bool {
+	desired, d := plan.records(ctx)
+	diags.Append(d...)
+	managed, d := state.records(ctx)
+	diags.Append(d...)
+	actual, err := r.apiClient.getRecordSet(ctx, plan.NameSuffix.ValueString())
+	diags.Append(frameworkDiagnostics(err)...)
+	if diags.HasError() {
+		return false
+	}
+
+	changes, d := recordSetChanges(desired, managed, actual)
+	diags.Append(d...)
+	if diags.HasError() {
+		return false
+	}
+
+	diags.Append(frameworkDiagnostics(r.apiClient.applyChanges(ctx, changes))...)
+	if actual, err = r.apiClient.getRecordSet(ctx, plan.NameSuffix.ValueString()); err != nil {
+		diags.Append(frameworkDiagnostics(err)...)
+	}
+
+	// Records that weren't deleted stay in state, so the next apply tries again
+	known := map[string]Record{}
+	for key, record := range desired {
+		known[key] = record
+	}
+	for key, record := range managed {
+		// A record that changes its type is only replaced in state if the record of the new type was created
+		if replacement, ok := desired[key]; ok {
+			if _, created := actual[newRecordKey(replacement)]; created || newRecordKey(replacement) == newRecordKey(record) {
+				continue
+			}
+		}
+		known[key] = record
+	}
+	plan.setRecords(known, actual)
+	return true
+}
+
+// recordSetChanges Returns the changes that turn the managed records into the desired ones, deletions first so
+// a record can change its type. Records are matched by name and type, records of other types with the same name
+// are never touched. A desired record that exists but isn't managed is an error, as somebody else may depend on it.
+func recordSetChanges(desired map[string]Record, managed map[string]Record, actual map[recordKey]Record) ([]RecordChange, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+
+	isManaged := map[recordKey]bool{}
+	for _, record := range managed {
+		isManaged[newRecordKey(record)] = true
+	}
+	isDesired := map[recordKey]bool{}
+	for _, record := range desired {
+		isDesired[newRecordKey(record)] = true
+	}
+
+	var deletions, changes []RecordChange
+	for _, record := range managed {
+		if current, exists := actual[newRecordKey(record)]; exists && !isDesired[newRecordKey(record)] {
+			deletions = append(deletions, RecordChange{Action: ChangeActionDelete, Record: current})
+		}
+	}
+	for _, record := range desired {
+		key := newRecordKey(record)
+		current, exists := actual[key]
+		record.RRType = key.rrtype
+		switch {
+		case exists && !isManaged[key]:
+			diags.AddError(fmt.Sprintf("Record %s exists already", record.Name),
+				fmt.Sprintf("The %s record is not part of this record set yet, please delete it or remove it from records.", key.rrtype))
+		case !exists:
+			changes = append(changes, RecordChange{Action: ChangeActionCreate, Record: record})
+		case canonicalRecord(current) != canonicalRecord(record):
+			changes = append(changes, RecordChange{Action: ChangeActionUpdate, Record: record})
+		}
+	}
+
+	// Map iteration is random, a stable order keeps change sets comparable
+	slices.SortFunc(deletions, compareRecordChanges)
+	slices.SortFunc(changes, compareRecordChanges)
+	return append(deletions, changes...), diags
+}
+
+func compareRecordChanges(a RecordChange, b RecordChange) int {
+	return cmp.Or(strings.Compare(a.Record.Name, b.Record.Name), strings.Compare(a.Record.RRType, b.Record.RRType))
+}
+
+// recordKey Identifies a record by normalized name and type, one name can hold records of several types
+type recordKey struct {
+	name   string
+	rrtype string
+}
+
+func newRecordKey(record Record) recordKey {
+	return recordKey{name: canonicalName(record.Name), rrtype: strings.ToUpper(record.RRType)}
+}
+
+// getRecordSet Returns the records below nameSuffix by normalized name and type
+func (c *ApiClient) getRecordSet(ctx context.Context, nameSuffix string) (map[recordKey]Record, diag.Diagnostics) {
+	filter := RecordFilter{NameSuffix: canonicalName(nameSuffix)}
+	results, err := c.getRecords(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	records := map[recordKey]Record{}
+	for _, record := range results {
+		// Filtering on our side in case the API doesn't
+		if filter.matches(record) {
+			records[newRecordKey(record)] = record
+		}
+	}
+	return records, nil
+}
+
+// recordSetKeyRegex Matches the keys of the records attribute
+var recordSetKeyRegex = regexp.MustCompile(`^(@|[^.]+(\.[^.]+)*)$`)
+
+// recordSetName Returns the full name for a key of the records attribute
+func recordSetName(key string, nameSuffix string) string {
+	if key == recordSetApex {
+		return canonicalName(nameSuffix)
+	}
+	return canonicalName(key + "." + canonicalName(nameSuffix))
+}
+
+// records Converts the records attribute into API objects by key
+func (m recordSetResourceModel) records(ctx context.Context) (map[string]Record, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+	records := map[string]Record{}
+	if m.Records.IsNull() || m.Records.IsUnknown() {
+		return records, diags
+	}
+
+	var entries map[string]recordSetRecordModel
+	diags.Append(m.Records.ElementsAs(ctx, &entries, false)...)
+	keys := map[string]string{}
+	for key, entry := range entries {
+		name := recordSetName(key, m.NameSuffix.ValueString())
+		if other, ok := keys[name]; ok {
+			diags.AddAttributeError(path.Root("records"), "Duplicate record name",
+				fmt.Sprintf("The keys %q and %q both stand for %s.", other, key, name))
+		}
+		keys[name] = key
+		records[key] = Record{
+			Name:   name,
+			RRType: entry.RRType.ValueString(),
+			Value:  entry.Value.ValueString(),
+			TTL:    int(m.TTL.ValueInt64()),
+		}
+	}
+	return records, diags
+}
+
+// setRecords Sets the records attribute to the known records that exist in the API with the same type. Values
+// are kept as known if the API returns an equivalent form. A record with another TTL shows up as change of ttl, so
+// the next apply corrects it.
+func (m *recordSetResourceModel) setRecords(known map[string]Record, actual map[recordKey]Record) {
+	elements := map[string]attr.Value{}
+	for key, record := range known {
+		current, ok := actual[newRecordKey(record)]
+		if !ok {
+			continue
+		}
+		if int64(current.TTL) != m.TTL.ValueInt64() {
+			m.TTL = types.Int64Value(int64(current.TTL))
+		}
+		if canonicalValue(current.RRType, current.Value) != canonicalValue(record.RRType, record.Value) {
+			record.Value = current.Value
+		}
+		elements[key] = types.ObjectValueMust(recordSetRecordType.AttrTypes, map[string]attr.Value{
+			"rrtype": types.StringValue(record.RRType),
+			"value":  types.StringValue(record.Value),
+		})
+	}
+	m.Records = types.MapValueMust(recordSetRecordType, elements)
+}
@@ -0,0 +1,154 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRecordSetChanges(t *testing.T) {
+	record := func(name string, rrtype string, value string) Record {
+		return Record{Name: name, RRType: rrtype, Value: value, TTL: 3600}
+	}
+	www := record("www.example.net", "CNAME", "app.example.net")
+	wwwTXT := record("www.example.net", "TXT", "owned by somebody else")
+	api := record("api.example.net", "CNAME", "api.edgekey.net")
+
+	tests := []struct {
+		name    string
+		desired map[string]Record
+		managed map[string]Record
+		actual  []Record
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "create",
+			desired: map[string]Record{"www": www},
+			want:    []string{"CREATE www.example.net CNAME app.example.net"},
+		},
+		{
+			name:    "unchanged in another form",
+			desired: map[string]Record{"www": record("www.example.net", "cname", "App.Example.net.")},
+			managed: map[string]Record{"www": www},
+			actual:  []Record{www},
+		},
+		{
+			name:    "update value",
+			desired: map[string]Record{"www": record("www.example.net", "CNAME", "other.example.net")},
+			managed: map[string]Record{"www": www},
+			actual:  []Record{www, wwwTXT},
+			want:    []string{"UPDATE www.example.net CNAME other.example.net"},
+		},
+		{
+			name:    "update ttl",
+			desired: map[string]Record{"www": www},
+			managed: map[string]Record{"www": www},
+			actual:  []Record{{Name: www.Name, RRType: www.RRType, Value: www.Value, TTL: 60}},
+			want:    []string{"UPDATE www.example.net CNAME app.example.net"},
+		},
+		{
+			name:    "change type",
+			desired: map[string]Record{"api": record("api.example.net", "txt", "token")},
+			managed: map[string]Record{"api": api},
+			actual:  []Record{api},
+			want:    []string{"DELETE api.example.net CNAME api.edgekey.net", "CREATE api.example.net TXT token"},
+		},
+		{
+			name:    "change type next to a record of another type",
+			desired: map[string]Record{"www": record("www.example.net", "A", "192.0.2.10")},
+			managed: map[string]Record{"www": www},
+			actual:  []Record{www, wwwTXT},
+			want:    []string{"DELETE www.example.net CNAME app.example.net", "CREATE www.example.net A 192.0.2.10"},
+		},
+		{
+			name:    "remove keeps a record of another type",
+			desired: map[string]Record{"api": api},
+			managed: map[string]Record{"api": api, "www": www},
+			actual:  []Record{api, www, wwwTXT},
+			want:    []string{"DELETE www.example.net CNAME app.example.net"},
+		},
+		{
+			name:    "remove a record deleted outside of Terraform",
+			managed: map[string]Record{"www": www},
+			actual:  []Record{wwwTXT},
+		},
+		{
+			name:    "create next to a record of another type",
+			desired: map[string]Record{"www": www},
+			actual:  []Record{wwwTXT},
+			want:    []string{"CREATE www.example.net CNAME app.example.net"},
+		},
+		{
+			name:    "existing record of the same type",
+			desired: map[string]Record{"www": www, "api": api},
+			actual:  []Record{record("www.example.net", "cname", "other.example.net")},
+			wantErr: "Record www.example.net exists already",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := map[recordKey]Record{}
+			for _, record := range tt.actual {
+				actual[newRecordKey(record)] = record
+			}
+
+			changes, diags := recordSetChanges(tt.desired, tt.managed, actual)
+			if tt.wantErr != "" {
+				if !diags.HasError() || diags[0].Summary() != tt.wantErr {
+					t.Errorf("recordSetChanges() = %v, want %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("recordSetChanges() = %v", diags)
+			}
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, fmt.Sprintf("%s %s %s %s", change.Action, change.Record.Name, change.Record.RRType, change.Record.Value))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("recordSetChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordSetDelete(t *testing.T) {
+	www := Record{Name: "www.example.net", RRType: "CNAME", Value: "app.example.net", TTL: 3600}
+	wwwTXT := Record{Name: "www.example.net", RRType: "TXT", Value: "owned by somebody else", TTL: 300}
+
+	// The record of api was deleted outside of Terraform
+	api := &recordsAPI{records: []Record{www, wwwTXT}}
+	r := NewRecordSetResource().(*recordSetResource)
+	r.apiClient = newTestAPI(t, api)
+	entry := func(rrtype string, value string) attr.Value {
+		return types.ObjectValueMust(recordSetRecordType.AttrTypes, map[string]attr.Value{
+			"rrtype": types.StringValue(rrtype),
+			"value":  types.StringValue(value),
+		})
+	}
+	state := testResourceState(t, r, map[string]attr.Value{
+		"id":          types.StringValue("example.net"),
+		"name_suffix": types.StringValue("example.net"),
+		"ttl":         types.Int64Value(3600),
+		"records": types.MapValueMust(recordSetRecordType, map[string]attr.Value{
+			"www": entry("CNAME", "app.example.net"),
+			"api": entry("CNAME", "api.edgekey.net"),
+		}),
+	})
+
+	var resp resource.DeleteResponse
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete() = %v", resp.Diagnostics)
+	}
+	if len(api.records) != 1 || api.records[0] != wwwTXT {
+		t.Errorf("records = %v, want only the TXT record left", api.records)
+	}
+}
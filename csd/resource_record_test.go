@@ -0,0 +1,161 @@
+package csd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testResourceState Returns a state of the resource holding values, all other attributes are null
+func testResourceState(t *testing.T, r resource.Resource, values map[string]attr.Value) tfsdk.State {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	raw := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		raw[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		terraformValue, err := value.ToTerraformValue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw[name] = terraformValue
+	}
+	return tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, raw)}
+}
+
+func TestRecordResourceValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := NewRecordResource().(*recordResource)
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	waitType := objectType.AttributeTypes["wait_for_propagation"].(tftypes.List)
+
+	wait := tftypes.NewValue(waitType, []tftypes.Value{
+		tftypes.NewValue(waitType.ElementType, map[string]tftypes.Value{
+			"resolvers":     tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+			"timeout":       tftypes.NewValue(tftypes.String, "1m"),
+			"poll_interval": tftypes.NewValue(tftypes.String, "5s"),
+		}),
+	})
+	noWait := tftypes.NewValue(waitType, []tftypes.Value{})
+
+	tests := []struct {
+		name    string
+		rrtype  tftypes.Value
+		wait    tftypes.Value
+		wantErr bool
+	}{
+		{name: "CNAME with wait", rrtype: tftypes.NewValue(tftypes.String, "CNAME"), wait: wait},
+		{name: "lower case txt with wait", rrtype: tftypes.NewValue(tftypes.String, "txt"), wait: wait},
+		{name: "MX with wait", rrtype: tftypes.NewValue(tftypes.String, "MX"), wait: wait, wantErr: true},
+		{name: "MX without wait", rrtype: tftypes.NewValue(tftypes.String, "MX"), wait: noWait},
+		{name: "unknown type with wait", rrtype: tftypes.NewValue(tftypes.String, tftypes.UnknownValue), wait: wait},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]tftypes.Value{}
+			for name, attributeType := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(attributeType, nil)
+			}
+			values["name"] = tftypes.NewValue(tftypes.String, "app.example.net")
+			values["value"] = tftypes.NewValue(tftypes.String, "10 mail.example.net")
+			values["rrtype"] = tt.rrtype
+			values["wait_for_propagation"] = tt.wait
+
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(ctx, req, &resp)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("ValidateConfig() = %v, want error %v", resp.Diagnostics, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRecordResourceOtherTypeWithSameName Manages a CNAME record next to a TXT record with the same name, which the
+// API returns when asked for the name only
+func TestRecordResourceOtherTypeWithSameName(t *testing.T) {
+	ctx := context.Background()
+	txt := Record{Name: "txt.example.net", RRType: "TXT", Value: "verification", TTL: 300}
+	cname := Record{Name: "txt.example.net", RRType: "CNAME", Value: "other.example.net", TTL: 60}
+	api := &recordsAPI{records: []Record{txt, cname}}
+	r := NewRecordResource().(*recordResource)
+	r.apiClient = newTestAPI(t, api)
+
+	var identitySchemaResp resource.IdentitySchemaResponse
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &identitySchemaResp)
+	identityType := identitySchemaResp.IdentitySchema.Type().TerraformType(ctx)
+	identity := &tfsdk.ResourceIdentity{Schema: identitySchemaResp.IdentitySchema, Raw: tftypes.NewValue(identityType, map[string]tftypes.Value{
+		"name":   tftypes.NewValue(tftypes.String, "txt.example.net"),
+		"rrtype": tftypes.NewValue(tftypes.String, "CNAME"),
+	})}
+	emptyIdentity := func() *tfsdk.ResourceIdentity {
+		return &tfsdk.ResourceIdentity{Schema: identity.Schema, Raw: tftypes.NewValue(identityType, nil)}
+	}
+	wantState := func(state tfsdk.State, want Record) {
+		t.Helper()
+		var got recordResourceModel
+		state.Get(ctx, &got)
+		if got.record() != want {
+			t.Errorf("state = %+v, want %+v", got.record(), want)
+		}
+	}
+
+	emptyState := testResourceState(t, r, nil)
+	emptyState.Raw = tftypes.NewValue(emptyState.Raw.Type(), nil)
+	importResp := resource.ImportStateResponse{State: emptyState, Identity: emptyIdentity()}
+	r.ImportState(ctx, resource.ImportStateRequest{Identity: identity}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("ImportState() = %v", importResp.Diagnostics)
+	}
+	wantState(importResp.State, cname)
+
+	state := testResourceState(t, r, map[string]attr.Value{
+		"id":     types.StringValue(cname.Name),
+		"name":   types.StringValue(cname.Name),
+		"rrtype": types.StringValue(cname.RRType),
+		"value":  types.StringValue(cname.Value),
+		"ttl":    types.Int64Value(int64(cname.TTL)),
+	})
+	readResp := resource.ReadResponse{State: state, Identity: emptyIdentity()}
+	r.Read(ctx, resource.ReadRequest{State: state}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", readResp.Diagnostics)
+	}
+	wantState(readResp.State, cname)
+
+	updated := cname
+	updated.Value = "new.example.net"
+	plan := testResourceState(t, r, map[string]attr.Value{
+		"id":     types.StringValue(updated.Name),
+		"name":   types.StringValue(updated.Name),
+		"rrtype": types.StringValue(updated.RRType),
+		"value":  types.StringValue(updated.Value),
+		"ttl":    types.Int64Value(int64(updated.TTL)),
+	})
+	updateResp := resource.UpdateResponse{State: state, Identity: emptyIdentity()}
+	r.Update(ctx, resource.UpdateRequest{Plan: tfsdk.Plan(plan), State: state}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update() = %v", updateResp.Diagnostics)
+	}
+	wantState(updateResp.State, updated)
+
+	var deleteResp resource.DeleteResponse
+	r.Delete(ctx, resource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Delete() = %v", deleteResp.Diagnostics)
+	}
+	if len(api.records) != 1 || api.records[0] != txt {
+		t.Errorf("records = %v, want only the TXT record left", api.records)
+	}
+}
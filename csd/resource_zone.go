@@ -0,0 +1,28 @@
+package csd
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// zoneResource Brings back the v1 resource name on top of the zone delegation code. Existing state of
+// csd_zone can be moved to csd_zone_delegation with removed and import blocks, without touching the NS records.
+type zoneResource struct {
+	zoneDelegationResource
+}
+
+func NewZoneResource() resource.Resource {
+	return &zoneResource{}
+}
+
+func (r *zoneResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone"
+}
+
+func (r *zoneResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	r.zoneDelegationResource.Schema(ctx, req, resp)
+	resp.Schema.MarkdownDescription = "Deprecated alias of `csd_zone_delegation` to migrate from version 1.x without downtime."
+	resp.Schema.DeprecationMessage = "csd_zone is deprecated, move it to csd_zone_delegation with a removed and an import block. " +
+		"See https://github.com/idealo/terraform-provider-csd/tree/main#upgrade-from-v1x-to-v2x"
+}
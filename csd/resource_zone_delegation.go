@@ -2,138 +2,514 @@ package csd
 
 import (
 	"context"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &zoneDelegationResource{}
+	_ resource.ResourceWithConfigure   = &zoneDelegationResource{}
+	_ resource.ResourceWithImportState = &zoneDelegationResource{}
+	_ resource.ResourceWithModifyPlan  = &zoneDelegationResource{}
+	_ resource.ResourceWithIdentity    = &zoneDelegationResource{}
 )
 
-func resourceZoneDelegation() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceZoneDelegationCreate,
-		ReadContext:   resourceZoneDelegationRead,
-		UpdateContext: resourceZoneDelegationUpdate,
-		DeleteContext: resourceZoneDelegationDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Description: "FQDN of the DNS zone",
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
+type zoneDelegationResource struct {
+	apiClient *ApiClient
+}
+
+type zoneDelegationResourceModel struct {
+	ID                 types.String   `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	NameServers        types.List     `tfsdk:"name_servers"`
+	DSRecords          types.List     `tfsdk:"ds_records"`
+	NameServerCheck    types.String   `tfsdk:"name_server_check"`
+	WaitForDelegation  []waitModel    `tfsdk:"wait_for_delegation"`
+	DelegationStatus   types.String   `tfsdk:"delegation_status"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// zoneDelegationIdentityModel Identifies a zone delegation in import blocks
+type zoneDelegationIdentityModel struct {
+	Name types.String `tfsdk:"name"`
+}
+
+func NewZoneDelegationResource() resource.Resource {
+	return &zoneDelegationResource{}
+}
+
+func (r *zoneDelegationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_delegation"
+}
+
+func (r *zoneDelegationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Name of the zone delegation",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"name_servers": {
-				Description: "List of authoritative name servers for the zone",
-				Type:        schema.TypeList,
-				Required:    true,
-				MinItems:    2,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+			"name": schema.StringAttribute{
+				MarkdownDescription: "FQDN of the DNS zone",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"name_servers": schema.ListAttribute{
+				MarkdownDescription: "List of authoritative name servers for the zone",
+				ElementType:         types.StringType,
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(2),
+				},
+			},
+			"ds_records": schema.ListAttribute{
+				MarkdownDescription: "DS records to enable DNSSEC for the zone, formatted as `<key tag> <algorithm> <digest type> <digest>` " +
+					"like the `ds_record` attribute of `aws_route53_key_signing_key`. If not set, DS records managed elsewhere are left alone, " +
+					"set it to `[]` to remove all of them.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(dsRecordValidator{}),
+				},
+				PlanModifiers: []planmodifier.List{
+					equivalentDSRecords{},
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name_server_check": schema.StringAttribute{
+				MarkdownDescription: "Query `name_servers` for SOA and NS records of the zone and report servers that are unreachable, " +
+					"not authoritative or disagree with each other. One of `off`, `warn` or `error`; `error` also checks during plan " +
+					"if the name servers are known.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(nameServerCheckOff),
+				Validators: []validator.String{
+					stringvalidator.OneOf(nameServerCheckOff, nameServerCheckWarn, nameServerCheckError),
+				},
+			},
+			"delegation_status": schema.StringAttribute{
+				MarkdownDescription: "Whether the parent zone delegates to `name_servers` (`live`), not yet (`pending`) " +
+					"or it wasn't checked because `wait_for_delegation` is not configured (`unknown`)",
+				Computed: true,
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Prevents destroying the zone delegation, it has to be set to `false` in a prior apply to allow deletion. " +
+					"Defaults to `true` for new and imported zone delegations.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"wait_for_delegation": waitBlock("Wait until the parent zone name servers delegate to `name_servers` after changes. " +
+				"If that times out after creating the zone delegation, it is only reported as warning and `delegation_status` stays `pending`."),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+	}
+}
+
+func (r *zoneDelegationResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"name": identityschema.StringAttribute{
+				Description:       "FQDN of the DNS zone",
+				RequiredForImport: true,
+			},
 		},
 	}
 }
 
-func resourceZoneDelegationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*ApiClient)
+func (r *zoneDelegationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.apiClient = apiClientFromProviderData(req.ProviderData, &resp.Diagnostics)
+}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+// ModifyPlan Keeps the delegation status unless the delegation changes, and fails the plan early on lame
+// delegations if name_server_check is set to error and the name servers are already known
+func (r *zoneDelegationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
 
-	zoneDelegation := ZoneDelegation{
-		Name:        d.Get("name").(string),
-		NameServers: []string{},
+	var plan, state zoneDelegationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	}
-	for _, ns := range d.Get("name_servers").([]interface{}) {
-		zoneDelegation.NameServers = append(zoneDelegation.NameServers, ns.(string))
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	result, err := apiClient.createZoneDelegation(zoneDelegation)
-	if err != nil {
-		return err
+	if !req.State.Raw.IsNull() && plan.NameServers.Equal(state.NameServers) && plan.DSRecords.Equal(state.DSRecords) &&
+		equalWaitBlocks(plan.WaitForDelegation, state.WaitForDelegation) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("delegation_status"), state.DelegationStatus)...)
 	}
 
-	d.SetId(result.Name)
-	if err := d.Set("name", result.Name); err != nil {
-		return diag.FromErr(err)
+	if plan.NameServerCheck.ValueString() != nameServerCheckError || plan.Name.IsUnknown() || plan.NameServers.IsUnknown() {
+		return
 	}
-	if err := d.Set("name_servers", result.NameServers); err != nil {
-		return diag.FromErr(err)
+	if !req.State.Raw.IsNull() && plan.Name.Equal(state.Name) && plan.NameServers.Equal(state.NameServers) {
+		return
 	}
 
-	return diags
+	zoneDelegation, diags := plan.zoneDelegation(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if problems := checkNameServers(ctx, zoneDelegation); len(problems) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("name_servers"),
+			fmt.Sprintf("Name servers of %s don't serve the zone properly", zoneDelegation.Name),
+			"- "+strings.Join(problems, "\n- "))
+	}
 }
 
-func resourceZoneDelegationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*ApiClient)
+func (r *zoneDelegationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan zoneDelegationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	zoneDelegation, diags := plan.zoneDelegation(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(frameworkDiagnostics(nameServerCheckDiagnostics(ctx, plan.NameServerCheck.ValueString(), zoneDelegation))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	result, err := r.apiClient.createZoneDelegation(ctx, zoneDelegation)
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	name := d.Id()
+	plan.ID = types.StringValue(result.Name)
+	// Protect new zone delegations unless explicitly configured otherwise
+	if plan.DeletionProtection.IsUnknown() {
+		plan.DeletionProtection = types.BoolValue(true)
+	}
+	resp.Diagnostics.Append(plan.setZoneDelegation(ctx, result)...)
+	// delegation_status tells if the wait failed, the zone delegation itself is fine
+	resp.Diagnostics.Append(createdWaitDiagnostics(plan.setDelegationStatus(ctx, result, true))...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newZoneDelegationIdentity(plan.ID.ValueString()))...)
+}
 
-	zoneDelegation, err := apiClient.getZoneDelegation(name)
-	if err != nil {
-		return err
+func (r *zoneDelegationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state zoneDelegationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// sets the response body (zoneDelegation object) to Terraform zone_delegation data source
-	if err := d.Set("name", zoneDelegation.Name); err != nil {
-		return diag.FromErr(err)
+	timeout, diags := state.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err := d.Set("name_servers", zoneDelegation.NameServers); err != nil {
-		return diag.FromErr(err)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	zoneDelegation, err := r.apiClient.getZoneDelegation(ctx, state.ID.ValueString())
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	return diags
+	// Imported zone delegations don't have the defaults yet
+	if state.NameServerCheck.IsNull() {
+		state.NameServerCheck = types.StringValue(nameServerCheckOff)
+	}
+	resp.Diagnostics.Append(state.setZoneDelegation(ctx, zoneDelegation)...)
+	resp.Diagnostics.Append(state.setDelegationStatus(ctx, zoneDelegation, false)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newZoneDelegationIdentity(state.ID.ValueString()))...)
 }
 
-func resourceZoneDelegationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Check for resource changes (only name servers are relevant at the moment)
-	if d.HasChange("name_servers") {
-		apiClient := m.(*ApiClient)
+func (r *zoneDelegationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state zoneDelegationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-		name := d.Id()
-		zoneDelegation := ZoneDelegation{
-			Name:        name,
-			NameServers: []string{},
-		}
-		for _, ns := range d.Get("name_servers").([]interface{}) {
-			zoneDelegation.NameServers = append(zoneDelegation.NameServers, ns.(string))
-		}
+	timeout, diags := plan.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if plan.DeletionProtection.IsUnknown() {
+		plan.DeletionProtection = types.BoolValue(state.DeletionProtection.ValueBool())
+	}
 
-		result, err := apiClient.updateZoneDelegation(zoneDelegation)
-		if err != nil {
-			return err
+	// Check for resource changes (only name servers and DS records are relevant at the moment)
+	if plan.NameServers.Equal(state.NameServers) && plan.DSRecords.Equal(state.DSRecords) {
+		// The wait block changed, so check the delegation once more
+		if plan.DelegationStatus.IsUnknown() {
+			zoneDelegation, diags := plan.zoneDelegation(ctx)
+			resp.Diagnostics.Append(diags...)
+			resp.Diagnostics.Append(plan.setDelegationStatus(ctx, zoneDelegation, false)...)
 		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, newZoneDelegationIdentity(plan.ID.ValueString()))...)
+		return
+	}
+
+	zoneDelegation, diags := plan.zoneDelegation(ctx)
+	resp.Diagnostics.Append(diags...)
+	var configDSRecords types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ds_records"), &configDSRecords)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	zoneDelegation.Name = state.ID.ValueString()
+	// The plan holds the DS records from state if they are not configured, they may be managed elsewhere
+	if configDSRecords.IsNull() {
+		zoneDelegation.DSRecords = nil
+	}
 
-		if err := d.Set("name", result.Name); err != nil {
-			return diag.FromErr(err)
+	if !plan.NameServers.Equal(state.NameServers) {
+		resp.Diagnostics.Append(frameworkDiagnostics(nameServerCheckDiagnostics(ctx, plan.NameServerCheck.ValueString(), zoneDelegation))...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
-		if err := d.Set("name_servers", result.NameServers); err != nil {
-			return diag.FromErr(err)
+	}
+
+	result, err := r.apiClient.updateZoneDelegation(ctx, zoneDelegation)
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(plan.setZoneDelegation(ctx, result)...)
+	resp.Diagnostics.Append(plan.setDelegationStatus(ctx, result, true)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newZoneDelegationIdentity(plan.ID.ValueString()))...)
+}
+
+func (r *zoneDelegationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state zoneDelegationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name := state.ID.ValueString()
+
+	// Long NS TTLs turn an accidental destroy into an extended outage
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Zone delegation is protected against deletion",
+			fmt.Sprintf("Set deletion_protection = false for %s and apply that change before destroying it. "+
+				"Keep in mind that resolvers may cache the NS records for up to 2 days.", name))
+		return
+	}
+
+	resp.Diagnostics.Append(frameworkDiagnostics(r.apiClient.deleteZoneDelegation(ctx, name))...)
+}
+
+// ImportState Imports an existing zone delegation by name or by identity, protected against deletion like new ones
+func (r *zoneDelegationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	name := req.ID
+	if name == "" {
+		var identity zoneDelegationIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
+		name = identity.Name.ValueString()
+	}
+
+	zoneDelegation, err := r.apiClient.getZoneDelegation(ctx, canonicalName(name))
+	if isNotFound(err) {
+		resp.Diagnostics.AddError("Cannot import non-existent zone delegation",
+			fmt.Sprintf("There is no zone delegation named %s, please check the name.", name))
+		return
+	}
+	resp.Diagnostics.Append(frameworkDiagnostics(err)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	return resourceZoneDelegationRead(ctx, d, m)
+	model := zoneDelegationResourceModel{
+		NameServers: types.ListNull(types.StringType),
+		DSRecords:   types.ListNull(types.StringType),
+	}
+	resp.Diagnostics.Append(model.setZoneDelegation(ctx, zoneDelegation)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), zoneDelegation.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), model.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name_servers"), model.NameServers)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ds_records"), model.DSRecords)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), true)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, newZoneDelegationIdentity(zoneDelegation.Name))...)
 }
 
-func resourceZoneDelegationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	apiClient := m.(*ApiClient)
+// newZoneDelegationIdentity Returns the identity of a zone delegation
+func newZoneDelegationIdentity(name string) zoneDelegationIdentityModel {
+	return zoneDelegationIdentityModel{
+		Name: types.StringValue(canonicalName(name)),
+	}
+}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+// zoneDelegation Converts the Terraform values into an API object
+func (m zoneDelegationResourceModel) zoneDelegation(ctx context.Context) (ZoneDelegation, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
 
-	name := d.Id()
+	var nameServers, dsRecords []string
+	diags.Append(m.NameServers.ElementsAs(ctx, &nameServers, false)...)
+	// Unset or not yet known DS records are left as they are
+	if !m.DSRecords.IsNull() && !m.DSRecords.IsUnknown() {
+		dsRecords = []string{}
+		diags.Append(m.DSRecords.ElementsAs(ctx, &dsRecords, false)...)
+	}
 
-	if err := apiClient.deleteZoneDelegation(name); err != nil {
-		return err
+	zoneDelegation := ZoneDelegation{
+		Name:        m.Name.ValueString(),
+		NameServers: []string{},
+		DSRecords:   expandDSRecords(dsRecords),
+	}
+	zoneDelegation.NameServers = append(zoneDelegation.NameServers, nameServers...)
+
+	return zoneDelegation, diags
+}
+
+// setZoneDelegation Sets the Terraform values from an API object, keeping equivalent values as configured
+func (m *zoneDelegationResourceModel) setZoneDelegation(ctx context.Context, zoneDelegation ZoneDelegation) fwdiag.Diagnostics {
+	var diags fwdiag.Diagnostics
+
+	m.Name = keepEquivalent(m.Name, zoneDelegation.Name, func(a, b string) bool {
+		return canonicalName(a) == canonicalName(b)
+	})
+
+	var nameServers []string
+	if !m.NameServers.IsNull() && !m.NameServers.IsUnknown() {
+		diags.Append(m.NameServers.ElementsAs(ctx, &nameServers, false)...)
+	}
+	if !equalNameServers(nameServers, zoneDelegation.NameServers) {
+		m.NameServers = stringList(zoneDelegation.NameServers)
 	}
 
-	// d.SetId("") is automatically called assuming delete returns no errors, but
-	// it is added here for explicitness.
-	d.SetId("")
+	var dsRecords []string
+	if !m.DSRecords.IsNull() && !m.DSRecords.IsUnknown() {
+		diags.Append(m.DSRecords.ElementsAs(ctx, &dsRecords, false)...)
+	}
+	var values []string
+	for _, record := range zoneDelegation.DSRecords {
+		values = append(values, record.String())
+	}
+	// An unset list stays unset if there are no DS records
+	if m.DSRecords.IsUnknown() || !equalDSRecords(dsRecords, values) {
+		m.DSRecords = stringList(values)
+	}
 
 	return diags
 }
+
+// setDelegationStatus Checks if the zone delegation is live when wait_for_delegation is configured,
+// the check is retried until the wait timeout if wait is set
+func (m *zoneDelegationResourceModel) setDelegationStatus(ctx context.Context, zoneDelegation ZoneDelegation, wait bool) fwdiag.Diagnostics {
+	config, diags := waitConfig(ctx, m.WaitForDelegation)
+	if config == nil {
+		m.DelegationStatus = types.StringValue(delegationStatusUnknown)
+		return diags
+	}
+
+	if wait {
+		err := waitForZoneDelegation(ctx, config, zoneDelegation)
+		status := delegationStatusLive
+		if err.HasError() {
+			status = delegationStatusPending
+		}
+		m.DelegationStatus = types.StringValue(status)
+		return append(diags, frameworkDiagnostics(err)...)
+	}
+
+	m.DelegationStatus = types.StringValue(zoneDelegationStatus(ctx, config, zoneDelegation))
+	return diags
+}
+
+// equalNameServers Compares two lists of name servers regardless of order, case and trailing dots
+func equalNameServers(a []string, b []string) bool {
+	var canonicalA, canonicalB []string
+	for _, value := range a {
+		canonicalA = append(canonicalA, canonicalName(value))
+	}
+	for _, value := range b {
+		canonicalB = append(canonicalB, canonicalName(value))
+	}
+	slices.Sort(canonicalA)
+	slices.Sort(canonicalB)
+	return slices.Equal(canonicalA, canonicalB)
+}
+
+// stringList Converts a slice into a Terraform list, an empty slice into null
+func stringList(values []string) types.List {
+	if len(values) == 0 {
+		return types.ListNull(types.StringType)
+	}
+	elements := make([]attr.Value, len(values))
+	for i, value := range values {
+		elements[i] = types.StringValue(value)
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
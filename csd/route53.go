@@ -0,0 +1,102 @@
+package csd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// HostedZone describes a Route53 hosted zone managed together with its zone delegation
+type HostedZone struct {
+	Id          string
+	Name        string
+	Comment     string
+	NameServers []string
+	// RecordCount Includes the SOA and NS records Route53 creates with the zone
+	RecordCount int64
+}
+
+// newRoute53Client Creates a Route53 client with the credentials of the API client
+func newRoute53Client(c *ApiClient) *route53.Client {
+	options := route53.Options{
+		Region:      c.Region,
+		Credentials: credentials.NewStaticCredentialsProvider(c.AccessKeyId, c.SecretAccessKey, c.SessionToken),
+	}
+	// Allows testing against a local Route53 stand-in
+	if c.Route53Endpoint != "" {
+		options.BaseEndpoint = aws.String(c.Route53Endpoint)
+	}
+	return route53.New(options)
+}
+
+// createHostedZone Creates a public hosted zone and returns its name servers
+func createHostedZone(ctx context.Context, c *ApiClient, name string, comment string) (HostedZone, error) {
+	input := &route53.CreateHostedZoneInput{
+		Name: aws.String(name),
+		// Has to be unique for every zone, Route53 uses it to recognize retries of the same request
+		CallerReference: aws.String(fmt.Sprintf("terraform-provider-csd-%s-%d", name, time.Now().UnixNano())),
+	}
+	if comment != "" {
+		input.HostedZoneConfig = &route53types.HostedZoneConfig{Comment: aws.String(comment)}
+	}
+
+	output, err := newRoute53Client(c).CreateHostedZone(ctx, input)
+	if err != nil {
+		return HostedZone{}, err
+	}
+	return newHostedZone(output.HostedZone, output.DelegationSet), nil
+}
+
+// getHostedZone Looks up a hosted zone by its ID
+func getHostedZone(ctx context.Context, c *ApiClient, id string) (HostedZone, error) {
+	output, err := newRoute53Client(c).GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(id)})
+	if err != nil {
+		return HostedZone{}, err
+	}
+	return newHostedZone(output.HostedZone, output.DelegationSet), nil
+}
+
+// updateHostedZoneComment Changes the comment of a hosted zone, the only property that can be changed in place
+func updateHostedZoneComment(ctx context.Context, c *ApiClient, id string, comment string) error {
+	_, err := newRoute53Client(c).UpdateHostedZoneComment(ctx, &route53.UpdateHostedZoneCommentInput{
+		Id:      aws.String(id),
+		Comment: aws.String(comment),
+	})
+	return err
+}
+
+// deleteHostedZone Deletes a hosted zone, Route53 refuses this as long as it contains other records than SOA and NS
+func deleteHostedZone(ctx context.Context, c *ApiClient, id string) error {
+	_, err := newRoute53Client(c).DeleteHostedZone(ctx, &route53.DeleteHostedZoneInput{Id: aws.String(id)})
+	return err
+}
+
+// isNoSuchHostedZone Reports whether err means that the hosted zone doesn't exist (anymore)
+func isNoSuchHostedZone(err error) bool {
+	var notFound *route53types.NoSuchHostedZone
+	return errors.As(err, &notFound)
+}
+
+// newHostedZone Converts the Route53 types, IDs are returned without the /hostedzone/ prefix like in the AWS console
+func newHostedZone(zone *route53types.HostedZone, delegationSet *route53types.DelegationSet) HostedZone {
+	var hostedZone HostedZone
+	if zone != nil {
+		hostedZone.Id = strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/")
+		hostedZone.Name = canonicalName(aws.ToString(zone.Name))
+		if zone.Config != nil {
+			hostedZone.Comment = aws.ToString(zone.Config.Comment)
+		}
+		hostedZone.RecordCount = aws.ToInt64(zone.ResourceRecordSetCount)
+	}
+	if delegationSet != nil {
+		hostedZone.NameServers = delegationSet.NameServers
+	}
+	return hostedZone
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -17,11 +20,15 @@ type Authorizer struct {
 
 // signRequest prepares a request with proper AWS Signer v4 authentication
 func signRequest(request *http.Request, accessKeyId string, secretAccessKey string, sessionToken string) *Authorizer {
-	currentTime := time.Now().UTC()
+	return signRequestAt(request, accessKeyId, secretAccessKey, sessionToken, time.Now())
+}
+
+// signRequestAt signs the request like at currentTime, which allows checking the signature with a fixed time
+func signRequestAt(request *http.Request, accessKeyId string, secretAccessKey string, sessionToken string, currentTime time.Time) *Authorizer {
+	currentTime = currentTime.UTC()
 
 	const (
 		awsService         = "execute-api"
-		requestQuery       = ""
 		requestContentType = "application/json"
 		awsRegion          = "eu-central-1"
 		signedHeaders      = "content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token"
@@ -35,7 +42,7 @@ func signRequest(request *http.Request, accessKeyId string, secretAccessKey stri
 	requestPayloadHash := hashSHA256(body)
 
 	var requestHeaders = fmt.Sprintf("content-type:%v\nhost:%v\nx-amz-content-sha256:%x\nx-amz-date:%s\nx-amz-security-token:%s", requestContentType, request.Host, requestPayloadHash, currentTime.Format(timeFmt), sessionToken)
-	var canonicalRequest = fmt.Sprintf("%v\n%v\n%v\n%v\n\n%v\n%x", request.Method, request.URL.Path, requestQuery, requestHeaders, signedHeaders, requestPayloadHash)
+	var canonicalRequest = fmt.Sprintf("%v\n%v\n%v\n%v\n\n%v\n%x", request.Method, request.URL.Path, canonicalQuery(request.URL), requestHeaders, signedHeaders, requestPayloadHash)
 
 	var stringToSign = fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s/%s/%s/aws4_request\n%x", currentTime.Format(timeFmt), currentTime.Format(dateFmt), awsRegion, awsService, hashSHA256([]byte(canonicalRequest)))
 
@@ -54,6 +61,31 @@ func signRequest(request *http.Request, accessKeyId string, secretAccessKey stri
 	}
 }
 
+// canonicalQuery encodes the query parameters sorted by name as required by AWS Signer v4
+func canonicalQuery(requestURL *url.URL) string {
+	query := requestURL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parameters []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parameters = append(parameters, fmt.Sprintf("%s=%s", uriEncode(key), uriEncode(value)))
+		}
+	}
+	return strings.Join(parameters, "&")
+}
+
+// uriEncode escapes everything except unreserved characters, spaces become %20 instead of +
+func uriEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
 func hashSHA256(data []byte) []byte {
 	hash := sha256.New()
 	hash.Write(data)
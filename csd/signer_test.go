@@ -0,0 +1,114 @@
+package csd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+func TestURIEncode(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "www.example.net", want: "www.example.net"},
+		{value: "a-b_c.d~e", want: "a-b_c.d~e"},
+		{value: "two words", want: "two%20words"},
+		{value: "a+b", want: "a%2Bb"},
+		{value: "*.example.net", want: "%2A.example.net"},
+		{value: "path/with=signs&more", want: "path%2Fwith%3Dsigns%26more"},
+		{value: "ümlaut", want: "%C3%BCmlaut"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := uriEncode(tt.value); got != tt.want {
+				t.Errorf("uriEncode(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "none", query: "", want: ""},
+		{name: "sorted by name", query: "rrtype=CNAME&name_suffix=example.net", want: "name_suffix=example.net&rrtype=CNAME"},
+		{name: "repeated names sorted by value", query: "b=2&a=z&a=y", want: "a=y&a=z&b=2"},
+		{name: "space as plus", query: "value=two+words", want: "value=two%20words"},
+		{name: "escaped characters", query: "name_suffix=%2A.example.net&x=a%2Fb", want: "name_suffix=%2A.example.net&x=a%2Fb"},
+		{name: "empty value", query: "flag=&a=1", want: "a=1&flag="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL := &url.URL{Scheme: "https", Host: "csd.idealo.tools", Path: "/v2/records", RawQuery: tt.query}
+			if got := canonicalQuery(requestURL); got != tt.want {
+				t.Errorf("canonicalQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSignRequest Compares the signatures with the ones of the AWS SDK for the same headers and time
+func TestSignRequest(t *testing.T) {
+	signingTime := time.Date(2024, 3, 1, 12, 30, 45, 0, time.FixedZone("CET", 3600))
+	credentials := aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", SessionToken: "session-token"}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		payload string
+	}{
+		{name: "GET", method: http.MethodGet, path: "/v2/records/www.example.net"},
+		{name: "GET with query", method: http.MethodGet, path: "/v2/records?rrtype=CNAME&name_suffix=sub.example.net"},
+		{name: "GET with escaped query", method: http.MethodGet, path: "/v2/records?name_suffix=%2A.example.net&value=two+words"},
+		{name: "POST", method: http.MethodPost, path: "/v2/records", payload: `{"name":"www.example.net","rrtype":"CNAME","value":"app.example.net","ttl":3600}`},
+		{name: "DELETE", method: http.MethodDelete, path: "/v2/zone_delegations/app.example.net"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(tt.method, HostURL+tt.path, bytes.NewReader([]byte(tt.payload)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			authorizer := signRequestAt(request, credentials.AccessKeyID, credentials.SecretAccessKey, credentials.SessionToken, signingTime)
+			if authorizer.date != "20240301T113045Z" {
+				t.Errorf("date = %q, want the time in UTC", authorizer.date)
+			}
+
+			expected, err := http.NewRequest(tt.method, HostURL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected.Header.Set("content-type", "application/json")
+			expected.Header.Set("x-amz-content-sha256", fmt.Sprintf("%x", authorizer.payloadHash))
+			err = v4.NewSigner().SignHTTP(context.Background(), credentials, expected, fmt.Sprintf("%x", authorizer.payloadHash),
+				"execute-api", "eu-central-1", signingTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := signature(expected.Header.Get("Authorization"))
+			if got := signature(authorizer.authorizationHeaders); got == "" || got != want {
+				t.Errorf("signature = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// signature Returns the signature of an Authorization header
+func signature(authorization string) string {
+	_, value, _ := strings.Cut(authorization, "Signature=")
+	return value
+}
@@ -0,0 +1,306 @@
+package csd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// WaitConfig Controls how long and how often we poll name servers until a change is visible
+type WaitConfig struct {
+	Resolvers    []string
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// defaultWaitTimeout Fits into defaultTimeout with time left for the API calls
+const defaultWaitTimeout = "90s"
+
+// waitModel Maps the wait block of resources
+type waitModel struct {
+	Resolvers    types.List   `tfsdk:"resolvers"`
+	Timeout      types.String `tfsdk:"timeout"`
+	PollInterval types.String `tfsdk:"poll_interval"`
+}
+
+// waitBlock Returns the optional block that enables waiting for DNS changes to become visible
+func waitBlock(description string) schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		MarkdownDescription: description,
+		Validators: []validator.List{
+			listvalidator.SizeAtMost(1),
+		},
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"resolvers": schema.ListAttribute{
+					MarkdownDescription: "Addresses (`host` or `host:port`) of the name servers to query. " +
+						"Defaults to the authoritative name servers of the parent zone.",
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"timeout": schema.StringAttribute{
+					MarkdownDescription: "How long to wait, e.g. `5m`. Defaults to `90s`, which fits into the default resource timeouts " +
+						"of 2 minutes. Longer waits need longer resource timeouts as well, the wait ends with them.",
+					Optional: true,
+					Computed: true,
+					Default:  stringdefault.StaticString(defaultWaitTimeout),
+					Validators: []validator.String{
+						durationValidator{},
+					},
+				},
+				"poll_interval": schema.StringAttribute{
+					MarkdownDescription: "How long to pause between queries, e.g. `10s`",
+					Optional:            true,
+					Computed:            true,
+					Default:             stringdefault.StaticString("10s"),
+					Validators: []validator.String{
+						durationValidator{},
+					},
+				},
+			},
+		},
+	}
+}
+
+// durationValidator Makes sure a string attribute can be parsed as positive Go duration
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a positive duration like 30s or 5m"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if duration, err := time.ParseDuration(req.ConfigValue.ValueString()); err != nil || duration <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration",
+			fmt.Sprintf("%q is not a positive duration like 30s or 5m", req.ConfigValue.ValueString()))
+	}
+}
+
+// waitConfig Converts the wait block of a resource, returns nil if waiting is not enabled
+func waitConfig(ctx context.Context, blocks []waitModel) (*WaitConfig, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+	if len(blocks) == 0 {
+		return nil, diags
+	}
+	block := blocks[0]
+
+	// Durations were checked by durationValidator already
+	timeout, _ := time.ParseDuration(block.Timeout.ValueString())
+	pollInterval, _ := time.ParseDuration(block.PollInterval.ValueString())
+
+	config := &WaitConfig{
+		Timeout:      timeout,
+		PollInterval: pollInterval,
+	}
+	diags.Append(block.Resolvers.ElementsAs(ctx, &config.Resolvers, false)...)
+
+	return config, diags
+}
+
+// equalWaitBlocks Compares the wait blocks of plan and state
+func equalWaitBlocks(a []waitModel, b []waitModel) bool {
+	return slices.EqualFunc(a, b, func(a waitModel, b waitModel) bool {
+		return a.Resolvers.Equal(b.Resolvers) && a.Timeout.Equal(b.Timeout) && a.PollInterval.Equal(b.PollInterval)
+	})
+}
+
+// waitForRecord Polls the name servers until all of them serve the expected value for a record
+func waitForRecord(ctx context.Context, config *WaitConfig, record Record) diag.Diagnostics {
+	if !isQueryableType(record.RRType) {
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Can't wait for record %s", record.Name),
+			Detail:   fmt.Sprintf("Waiting supports the record types %s, but not %s.", strings.Join(queryableTypes(), ", "), record.RRType),
+		}}
+	}
+
+	servers := config.Resolvers
+	if len(servers) == 0 {
+		var err error
+		if servers, err = findAuthoritativeServers(ctx, record.Name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	expected := canonicalValue(record.RRType, record.Value)
+	pending, err := pollServers(ctx, config, servers, func(ctx context.Context, server string) (bool, error) {
+		answer, err := queryDNS(ctx, server, record.Name, record.RRType)
+		if err != nil {
+			return false, err
+		}
+		for _, value := range answer.Values {
+			if canonicalValue(record.RRType, value) == expected {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Record %s didn't propagate in time", record.Name),
+			Detail:   fmt.Sprintf("Name servers still not serving %q: %s (%s)", record.Value, strings.Join(pending, ", "), err),
+		}}
+	}
+
+	return nil
+}
+
+// createdWaitDiagnostics Turns errors of a wait right after creation into warnings. An error would taint the new
+// object, and deletion protection would then block its planned replacement.
+func createdWaitDiagnostics(diags fwdiag.Diagnostics) fwdiag.Diagnostics {
+	var result fwdiag.Diagnostics
+	for _, d := range diags {
+		if d.Severity() == fwdiag.SeverityError {
+			result.AddWarning(d.Summary(), d.Detail()+"\n\nIt was created anyway, please check it before depending on it.")
+			continue
+		}
+		result.Append(d)
+	}
+	return result
+}
+
+// Possible values of the delegation_status attribute
+const (
+	delegationStatusLive    = "live"
+	delegationStatusPending = "pending"
+	delegationStatusUnknown = "unknown"
+)
+
+// waitForZoneDelegation Polls the parent zone name servers until all of them delegate to the expected name servers
+func waitForZoneDelegation(ctx context.Context, config *WaitConfig, zoneDelegation ZoneDelegation) diag.Diagnostics {
+	servers, diags := delegationServers(ctx, config, zoneDelegation)
+	if diags.HasError() {
+		return diags
+	}
+
+	pending, err := pollServers(ctx, config, servers, func(ctx context.Context, server string) (bool, error) {
+		return isDelegatedBy(ctx, server, zoneDelegation)
+	})
+	if err != nil {
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Zone delegation %s didn't become live in time", zoneDelegation.Name),
+			Detail:   fmt.Sprintf("Name servers still not delegating to %s: %s (%s)", strings.Join(zoneDelegation.NameServers, ", "), strings.Join(pending, ", "), err),
+		}}
+	}
+
+	return nil
+}
+
+// zoneDelegationStatus Checks once whether all parent zone name servers delegate to the expected name servers
+func zoneDelegationStatus(ctx context.Context, config *WaitConfig, zoneDelegation ZoneDelegation) string {
+	servers, diags := delegationServers(ctx, config, zoneDelegation)
+	if diags.HasError() {
+		return delegationStatusUnknown
+	}
+
+	for _, server := range servers {
+		delegated, err := isDelegatedBy(ctx, server, zoneDelegation)
+		if err != nil {
+			return delegationStatusUnknown
+		}
+		if !delegated {
+			return delegationStatusPending
+		}
+	}
+
+	return delegationStatusLive
+}
+
+// delegationServers Returns the configured resolvers or the authoritative name servers of the parent zone
+func delegationServers(ctx context.Context, config *WaitConfig, zoneDelegation ZoneDelegation) ([]string, diag.Diagnostics) {
+	if len(config.Resolvers) > 0 {
+		return config.Resolvers, nil
+	}
+	servers, err := findAuthoritativeServers(ctx, zoneDelegation.Name)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return servers, nil
+}
+
+// isDelegatedBy Checks if server returns exactly the expected NS set for the delegated zone
+func isDelegatedBy(ctx context.Context, server string, zoneDelegation ZoneDelegation) (bool, error) {
+	answer, err := queryDNS(ctx, server, zoneDelegation.Name, "NS")
+	if err != nil {
+		return false, err
+	}
+
+	var actual []string
+	for _, value := range answer.Values {
+		actual = append(actual, canonicalName(value))
+	}
+	// A referral to a zone above the delegated one doesn't count
+	if answer.ReferralZone == canonicalName(zoneDelegation.Name) {
+		for _, value := range answer.Referral {
+			actual = append(actual, canonicalName(value))
+		}
+	}
+	var expected []string
+	for _, value := range zoneDelegation.NameServers {
+		expected = append(expected, canonicalName(value))
+	}
+	slices.Sort(actual)
+	slices.Sort(expected)
+
+	return slices.Equal(slices.Compact(actual), slices.Compact(expected)), nil
+}
+
+// pollServers Runs check against every server until all of them succeed or the wait times out.
+// The servers still failing the check are returned together with the error.
+func pollServers(ctx context.Context, config *WaitConfig, servers []string, check func(context.Context, string) (bool, error)) ([]string, error) {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	pending := slices.Clone(servers)
+	lastErrors := map[string]error{}
+	for {
+		var remaining []string
+		for _, server := range pending {
+			done, err := check(ctx, server)
+			if err != nil {
+				lastErrors[server] = err
+			}
+			if !done {
+				remaining = append(remaining, server)
+			}
+		}
+		pending = remaining
+		if len(pending) == 0 {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			err := ctx.Err()
+			if parent.Err() != nil {
+				// The resource timeout expired first, which is no reason to fail with a bare context error
+				err = fmt.Errorf("the resource timeout expired before the wait timeout of %s", config.Timeout)
+			}
+			if lastErr := lastErrors[pending[0]]; lastErr != nil {
+				return pending, fmt.Errorf("%w, last error: %s", err, lastErr)
+			}
+			return pending, err
+		case <-time.After(config.PollInterval):
+		}
+	}
+}
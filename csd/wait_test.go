@@ -0,0 +1,355 @@
+package csd
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// stubDNSServer Answers DNS queries over UDP from a map that tests change while it is running
+type stubDNSServer struct {
+	conn net.PacketConn
+
+	mutex sync.Mutex
+	// answers Holds the values by lower case name and type, referrals the NS records a parent zone refers with
+	answers   map[string][]string
+	referrals map[string][]string
+	queries   int
+}
+
+func newStubDNSServer(t *testing.T) *stubDNSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &stubDNSServer{conn: conn, answers: map[string][]string{}, referrals: map[string][]string{}}
+	t.Cleanup(func() { conn.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *stubDNSServer) address() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *stubDNSServer) set(name string, rrtype string, values ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.answers[name+" "+rrtype] = values
+}
+
+func (s *stubDNSServer) refer(zone string, nameServers ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.referrals[zone] = nameServers
+}
+
+func (s *stubDNSServer) queryCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.queries
+}
+
+func (s *stubDNSServer) serve() {
+	buffer := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		var query dnsmessage.Message
+		if err := query.Unpack(buffer[:n]); err != nil || len(query.Questions) != 1 {
+			continue
+		}
+		response := s.answer(query)
+		packed, err := response.Pack()
+		if err != nil {
+			continue
+		}
+		s.conn.WriteTo(packed, addr)
+	}
+}
+
+func (s *stubDNSServer) answer(query dnsmessage.Message) dnsmessage.Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queries++
+
+	question := query.Questions[0]
+	name := strings.TrimSuffix(strings.ToLower(question.Name.String()), ".")
+	rrtype := strings.TrimPrefix(question.Type.String(), "Type")
+	response := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.ID, Response: true},
+		Questions: query.Questions,
+	}
+
+	if zone, nameServers := s.referral(name); nameServers != nil {
+		for _, nameServer := range nameServers {
+			response.Authorities = append(response.Authorities, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(zone + "."), Type: dnsmessage.TypeNS, Class: dnsmessage.ClassINET},
+				Body:   &dnsmessage.NSResource{NS: dnsmessage.MustNewName(canonicalName(nameServer) + ".")},
+			})
+		}
+		return response
+	}
+
+	response.Authoritative = true
+	if !s.exists(name) {
+		response.RCode = dnsmessage.RCodeNameError
+	}
+	for _, value := range s.answers[name+" "+rrtype] {
+		header := dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 60}
+		var body dnsmessage.ResourceBody
+		switch question.Type {
+		case dnsmessage.TypeA:
+			var a [4]byte
+			copy(a[:], net.ParseIP(value).To4())
+			body = &dnsmessage.AResource{A: a}
+		case dnsmessage.TypeCNAME:
+			body = &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName(canonicalName(value) + ".")}
+		case dnsmessage.TypeNS:
+			body = &dnsmessage.NSResource{NS: dnsmessage.MustNewName(canonicalName(value) + ".")}
+		case dnsmessage.TypeTXT:
+			// Long TXT values come in several strings
+			body = &dnsmessage.TXTResource{TXT: strings.SplitAfter(value, " ")}
+		}
+		response.Answers = append(response.Answers, dnsmessage.Resource{Header: header, Body: body})
+	}
+	return response
+}
+
+// exists Reports whether name or a name below it has records of any type, otherwise it is NXDOMAIN
+func (s *stubDNSServer) exists(name string) bool {
+	for key := range s.answers {
+		if owner, _, _ := strings.Cut(key, " "); hasNameSuffix(owner, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// referral Finds the closest zone at or above name that is delegated
+func (s *stubDNSServer) referral(name string) (string, []string) {
+	for zone := name; zone != ""; {
+		if nameServers, ok := s.referrals[zone]; ok {
+			return zone, nameServers
+		}
+		_, zone, _ = strings.Cut(zone, ".")
+	}
+	return "", nil
+}
+
+func testWaitConfig(servers ...string) *WaitConfig {
+	return &WaitConfig{Resolvers: servers, Timeout: 2 * time.Second, PollInterval: 10 * time.Millisecond}
+}
+
+func TestWaitForRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		served []string
+	}{
+		{
+			name:   "CNAME with trailing dot and other case",
+			record: Record{Name: "www.example.net", RRType: "CNAME", Value: "www.example.net.edgekey.net"},
+			served: []string{"WWW.example.net.EdgeKey.net."},
+		},
+		{
+			name:   "lower case A",
+			record: Record{Name: "app.example.net", RRType: "a", Value: "192.0.2.10"},
+			served: []string{"192.0.2.10"},
+		},
+		{
+			name:   "TXT split into several strings",
+			record: Record{Name: "_verify.example.net", RRType: "TXT", Value: "token with some words"},
+			served: []string{"other", "token with some words"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStubDNSServer(t)
+			rrtype := strings.ToUpper(tt.record.RRType)
+
+			// The new value shows up after a while
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				server.set(tt.record.Name, rrtype, tt.served...)
+			}()
+
+			if diags := waitForRecord(context.Background(), testWaitConfig(server.address()), tt.record); diags.HasError() {
+				t.Fatalf("waitForRecord() = %v", diags)
+			}
+			if server.queryCount() < 2 {
+				t.Errorf("expected polling, got %d queries", server.queryCount())
+			}
+		})
+	}
+}
+
+func TestWaitForRecordTimeout(t *testing.T) {
+	server := newStubDNSServer(t)
+	server.set("www.example.net", "CNAME", "old.example.net")
+	config := testWaitConfig(server.address())
+	config.Timeout = 100 * time.Millisecond
+
+	diags := waitForRecord(context.Background(), config, Record{Name: "www.example.net", RRType: "CNAME", Value: "new.example.net"})
+	if !diags.HasError() || diags[0].Summary != "Record www.example.net didn't propagate in time" {
+		t.Fatalf("waitForRecord() = %v, want timeout", diags)
+	}
+	if !strings.Contains(diags[0].Detail, server.address()) {
+		t.Errorf("detail %q doesn't name the pending server", diags[0].Detail)
+	}
+}
+
+func TestWaitForRecordResourceTimeout(t *testing.T) {
+	server := newStubDNSServer(t)
+	server.set("www.example.net", "CNAME", "old.example.net")
+
+	// The resource timeout expires long before the wait timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	diags := waitForRecord(ctx, testWaitConfig(server.address()), Record{Name: "www.example.net", RRType: "CNAME", Value: "new.example.net"})
+	if !diags.HasError() || diags[0].Summary != "Record www.example.net didn't propagate in time" {
+		t.Fatalf("waitForRecord() = %v, want timeout", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "resource timeout expired before the wait timeout of 2s") {
+		t.Errorf("detail %q doesn't explain the resource timeout", diags[0].Detail)
+	}
+}
+
+func TestDefaultWaitTimeout(t *testing.T) {
+	timeout, err := time.ParseDuration(defaultWaitTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout >= defaultTimeout {
+		t.Errorf("defaultWaitTimeout %s doesn't fit into defaultTimeout %s", timeout, defaultTimeout)
+	}
+}
+
+func TestWaitForRecordUnsupportedType(t *testing.T) {
+	server := newStubDNSServer(t)
+
+	start := time.Now()
+	diags := waitForRecord(context.Background(), testWaitConfig(server.address()), Record{Name: "example.net", RRType: "MX", Value: "10 mail.example.net"})
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "not MX") {
+		t.Fatalf("waitForRecord() = %v, want unsupported type error", diags)
+	}
+	if time.Since(start) > time.Second || server.queryCount() != 0 {
+		t.Errorf("expected immediate failure, took %s and %d queries", time.Since(start), server.queryCount())
+	}
+}
+
+func TestIsDelegatedBy(t *testing.T) {
+	zoneDelegation := ZoneDelegation{
+		Name:        "app.example.net",
+		NameServers: []string{"ns-1.awsdns-01.org", "NS-2.awsdns-02.net."},
+	}
+
+	tests := []struct {
+		name    string
+		prepare func(server *stubDNSServer)
+		want    bool
+	}{
+		{
+			name: "referral to the expected name servers",
+			prepare: func(server *stubDNSServer) {
+				server.refer("app.example.net", "ns-2.awsdns-02.net.", "ns-1.awsdns-01.org.")
+			},
+			want: true,
+		},
+		{
+			name: "referral to other name servers",
+			prepare: func(server *stubDNSServer) {
+				server.refer("app.example.net", "ns-1.awsdns-01.org.", "ns-3.awsdns-03.com.")
+			},
+			want: false,
+		},
+		{
+			name: "referral to a subset of the name servers",
+			prepare: func(server *stubDNSServer) {
+				server.refer("app.example.net", "ns-1.awsdns-01.org.")
+			},
+			want: false,
+		},
+		{
+			name: "referral to a zone above",
+			prepare: func(server *stubDNSServer) {
+				server.refer("example.net", "ns-2.awsdns-02.net.", "ns-1.awsdns-01.org.")
+			},
+			want: false,
+		},
+		{
+			name: "authoritative NS answer",
+			prepare: func(server *stubDNSServer) {
+				server.set("app.example.net", "NS", "ns-1.awsdns-01.org.", "ns-2.awsdns-02.net.")
+			},
+			want: true,
+		},
+		{
+			name:    "not delegated at all",
+			prepare: func(server *stubDNSServer) {},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStubDNSServer(t)
+			tt.prepare(server)
+
+			got, err := isDelegatedBy(context.Background(), server.address(), zoneDelegation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("isDelegatedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForZoneDelegation(t *testing.T) {
+	zoneDelegation := ZoneDelegation{Name: "app.example.net", NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.net"}}
+	first := newStubDNSServer(t)
+	second := newStubDNSServer(t)
+	first.refer("app.example.net", "ns-1.awsdns-01.org.", "ns-2.awsdns-02.net.")
+	config := testWaitConfig(first.address(), second.address())
+
+	if got := zoneDelegationStatus(context.Background(), config, zoneDelegation); got != delegationStatusPending {
+		t.Errorf("zoneDelegationStatus() = %q, want %q", got, delegationStatusPending)
+	}
+
+	// The second parent zone server catches up later
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		second.refer("app.example.net", "ns-2.awsdns-02.net.", "ns-1.awsdns-01.org.")
+	}()
+	if diags := waitForZoneDelegation(context.Background(), config, zoneDelegation); diags.HasError() {
+		t.Fatalf("waitForZoneDelegation() = %v", diags)
+	}
+	if got := zoneDelegationStatus(context.Background(), config, zoneDelegation); got != delegationStatusLive {
+		t.Errorf("zoneDelegationStatus() = %q, want %q", got, delegationStatusLive)
+	}
+}
+
+func TestCreatedWaitDiagnostics(t *testing.T) {
+	var diags fwdiag.Diagnostics
+	diags.AddWarning("Name servers don't answer", "ns-1.awsdns-01.org timed out")
+	diags.AddError("Zone delegation app.example.net didn't become live in time", "Name servers still not delegating")
+
+	result := createdWaitDiagnostics(diags)
+	if result.HasError() {
+		t.Fatalf("createdWaitDiagnostics() = %v, want warnings only", result)
+	}
+	if len(result) != 2 || result[1].Summary() != "Zone delegation app.example.net didn't become live in time" ||
+		!strings.HasPrefix(result[1].Detail(), "Name servers still not delegating") {
+		t.Errorf("createdWaitDiagnostics() = %v, want both diagnostics kept", result)
+	}
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/idealo/terraform-provider-csd/csd"
 )
 
@@ -27,13 +33,29 @@ var (
 func main() {
 	var debugMode bool
 
-	// TODO: figure out how to use this
 	flag.BoolVar(&debugMode, "debug", false, "Set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	plugin.Serve(&plugin.ServeOpts{
-		Debug:        debugMode,
-		ProviderAddr: "idealo/csd",
-		ProviderFunc: csd.New(version, commit),
-	})
+	ctx := context.Background()
+
+	// The SDKv2 and the framework provider are served as one, each of them handles its own resources
+	providers := []func() tfprotov5.ProviderServer{
+		providerserver.NewProtocol5(csd.NewFramework(version, commit)()),
+		csd.New(version, commit)().GRPCProvider,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf5server.ServeOpt
+	if debugMode {
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+
+	err = tf5server.Serve("registry.terraform.io/idealo/csd", muxServer.ProviderServer, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }